@@ -0,0 +1,66 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ErikKalkoken/weatherapp/internal/config"
+	"github.com/ErikKalkoken/weatherapp/internal/statusbar"
+	"github.com/ErikKalkoken/weatherapp/internal/weather"
+)
+
+// runBar prints the current weather for a status bar.
+// Results are cached on disk, so that a bar polling frequently does not hit the APIs each time.
+func runBar(cfg config.Config, service *weather.Service, args []string) {
+	fs := flag.NewFlagSet("bar", flag.ExitOnError)
+	format := fs.String("format", "text", "output format: text or waybar")
+	maxAge := fs.Duration("cache", 10*time.Minute, "maximum age of cached weather data")
+	fs.Parse(args)
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		log.Fatal(err)
+	}
+	key, err := cacheKey(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	path := filepath.Join(dir, "weatherapp", fmt.Sprintf("snapshot-%s.json", key[:16]))
+	x, err := service.RefreshCached(path, key, *maxAge)
+	if err != nil {
+		log.Fatal(err)
+	}
+	switch *format {
+	case "text":
+		fmt.Println(statusbar.Text(x))
+	case "waybar":
+		data, err := statusbar.Waybar(x)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(string(data))
+	default:
+		log.Fatalf("unknown format: %s", *format)
+	}
+}
+
+// cacheKey returns a key for the settings the cached weather depends on.
+// It is a hash of the whole configuration, so that any change of the location, the units,
+// the APIs, the models or the alert rules invalidates the cache.
+func cacheKey(cfg config.Config) (string, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+	coordinates := "none"
+	if cfg.HasCoordinates() {
+		coordinates = fmt.Sprintf("%f,%f", cfg.Latitude, cfg.Longitude)
+	}
+	sum := sha256.Sum256(append(data, coordinates...))
+	return fmt.Sprintf("%x", sum), nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/ErikKalkoken/weatherapp/internal/ical"
+	"github.com/ErikKalkoken/weatherapp/internal/weather"
+)
+
+// runICS fetches the weather once and exports the daily forecasts as iCalendar file.
+func runICS(service *weather.Service, args []string) error {
+	fs := flag.NewFlagSet("ics", flag.ExitOnError)
+	output := fs.String("o", "", "write to this file instead of stdout")
+	fs.Parse(args)
+	x, err := service.Refresh()
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		return ical.Encode(os.Stdout, x.Location, x.Forecast.Daily, x.UpdatedAt)
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := ical.Encode(f, x.Location, x.Forecast.Daily, x.UpdatedAt); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
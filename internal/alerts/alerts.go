@@ -0,0 +1,260 @@
+// Package alerts evaluates threshold rules over weather forecasts and produces alerts.
+package alerts
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ErikKalkoken/weatherapp/internal/forecast"
+	"github.com/ErikKalkoken/weatherapp/internal/weathercode"
+)
+
+// Severity is the severity of an alert.
+type Severity uint
+
+const (
+	Info Severity = iota
+	Warning
+	Severe
+)
+
+var severityNames = map[Severity]string{
+	Info:    "info",
+	Warning: "warning",
+	Severe:  "severe",
+}
+
+func (s Severity) String() string {
+	return severityNames[s]
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(text []byte) error {
+	for k, v := range severityNames {
+		if v == string(text) {
+			*s = k
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid severity: %s", text)
+}
+
+// Hourly and daily metrics which can be used in rules.
+var (
+	hourlyMetrics = map[string]func(forecast.ForecastHour) float64{
+		"temperature":               func(f forecast.ForecastHour) float64 { return f.Temperature2m },
+		"precipitation_probability": func(f forecast.ForecastHour) float64 { return float64(f.PrecipitationProbability) },
+		"weather_code":              func(f forecast.ForecastHour) float64 { return float64(f.WeatherCode) },
+		"wind_gusts":                func(f forecast.ForecastHour) float64 { return f.WindGusts10m },
+	}
+	dailyMetrics = map[string]func(forecast.ForecastDay) float64{
+		"temperature_max":                func(f forecast.ForecastDay) float64 { return f.Temperature2mMax },
+		"temperature_min":                func(f forecast.ForecastDay) float64 { return f.Temperature2mMin },
+		"precipitation_probability_mean": func(f forecast.ForecastDay) float64 { return float64(f.PrecipitationProbabilityMean) },
+		"weather_code":                   func(f forecast.ForecastDay) float64 { return float64(f.WeatherCode) },
+		"wind_gusts_max":                 func(f forecast.ForecastDay) float64 { return f.WindGusts10mMax },
+	}
+)
+
+// Rule is a threshold rule, e.g. "wind gusts > 60 within the next 24 hours".
+//
+// A rule is evaluated over the hourly forecast when Daily is false
+// and over the daily forecast otherwise.
+// Thresholds are in the units of the forecast.
+type Rule struct {
+	Name      string        `toml:"name"`
+	Metric    string        `toml:"metric"`   // e.g. temperature, wind_gusts or weather_code
+	Operator  string        `toml:"operator"` // one of <, <=, >, >=, ==
+	Value     float64       `toml:"value"`
+	Within    time.Duration `toml:"within"`     // how far to look ahead. Zero means the whole forecast.
+	Daily     bool          `toml:"daily"`      // whether to evaluate the daily forecast
+	NightOnly bool          `toml:"night_only"` // whether to evaluate only hours during the night
+	Severity  Severity      `toml:"severity"`
+}
+
+// Validate reports an error when the rule is invalid.
+func (r Rule) Validate() error {
+	if r.Daily {
+		if _, ok := dailyMetrics[r.Metric]; !ok {
+			return fmt.Errorf("rule %q: invalid daily metric: %s", r.Name, r.Metric)
+		}
+		if r.NightOnly {
+			return fmt.Errorf("rule %q: night_only requires an hourly metric", r.Name)
+		}
+	} else if _, ok := hourlyMetrics[r.Metric]; !ok {
+		return fmt.Errorf("rule %q: invalid hourly metric: %s", r.Name, r.Metric)
+	}
+	if _, ok := operators[r.Operator]; !ok {
+		return fmt.Errorf("rule %q: invalid operator: %s", r.Name, r.Operator)
+	}
+	return nil
+}
+
+var operators = map[string]func(a, b float64) bool{
+	"<":  func(a, b float64) bool { return a < b },
+	"<=": func(a, b float64) bool { return a <= b },
+	">":  func(a, b float64) bool { return a > b },
+	">=": func(a, b float64) bool { return a >= b },
+	"==": func(a, b float64) bool { return a == b },
+}
+
+// extreme reports whether value a is more extreme than b for the operator of the rule.
+func (r Rule) extreme(a, b float64) bool {
+	if strings.HasPrefix(r.Operator, "<") {
+		return a < b
+	}
+	return a > b
+}
+
+// Alert is raised when a rule matches a forecast.
+type Alert struct {
+	Name     string    `json:"name"`
+	Severity Severity  `json:"severity"`
+	Start    time.Time `json:"start"`   // time of the first match
+	End      time.Time `json:"end"`     // time of the last match
+	Value    float64   `json:"value"`   // most extreme matching value
+	Message  string    `json:"message"` // human readable description
+}
+
+// Evaluate returns the alerts raised by rules for a forecast, ordered by severity and start time.
+func Evaluate(rules []Rule, r forecast.Result, now time.Time) []Alert {
+	alerts := make([]Alert, 0)
+	for _, rule := range rules {
+		var a Alert
+		var ok bool
+		if rule.Daily {
+			a, ok = evaluateDaily(rule, r.Daily, now)
+		} else {
+			a, ok = evaluateHourly(rule, append([]forecast.ForecastHour{r.Current}, r.Hourly...), now)
+		}
+		if ok {
+			a.Message = message(rule, a, r.Units)
+			alerts = append(alerts, a)
+		}
+	}
+	slices.SortStableFunc(alerts, func(a, b Alert) int {
+		return cmp.Or(cmp.Compare(b.Severity, a.Severity), a.Start.Compare(b.Start))
+	})
+	return alerts
+}
+
+func evaluateHourly(rule Rule, hours []forecast.ForecastHour, now time.Time) (Alert, bool) {
+	metric := hourlyMetrics[rule.Metric]
+	match := operators[rule.Operator]
+	var a Alert
+	var found bool
+	for _, h := range hours {
+		if rule.Within > 0 && h.Time.After(now.Add(rule.Within)) {
+			break
+		}
+		if rule.NightOnly && h.IsDay {
+			continue
+		}
+		v := metric(h)
+		if !match(v, rule.Value) {
+			continue
+		}
+		if !found {
+			a = Alert{Name: rule.Name, Severity: rule.Severity, Start: h.Time, Value: v}
+			found = true
+		} else if rule.extreme(v, a.Value) {
+			a.Value = v
+		}
+		a.End = h.Time
+	}
+	return a, found
+}
+
+func evaluateDaily(rule Rule, days []forecast.ForecastDay, now time.Time) (Alert, bool) {
+	metric := dailyMetrics[rule.Metric]
+	match := operators[rule.Operator]
+	var a Alert
+	var found bool
+	for _, d := range days {
+		if rule.Within > 0 && d.Time.After(now.Add(rule.Within)) {
+			break
+		}
+		v := metric(d)
+		if !match(v, rule.Value) {
+			continue
+		}
+		if !found {
+			a = Alert{Name: rule.Name, Severity: rule.Severity, Start: d.Time, Value: v}
+			found = true
+		} else if rule.extreme(v, a.Value) {
+			a.Value = v
+		}
+		a.End = d.Time
+	}
+	return a, found
+}
+
+// message returns a human readable description of an alert.
+func message(rule Rule, a Alert, units forecast.Units) string {
+	layout := "15:04"
+	if rule.Daily {
+		layout = "Mon"
+	}
+	when := a.Start.Local().Format(layout)
+	if !a.End.Equal(a.Start) {
+		when += " - " + a.End.Local().Format(layout)
+	}
+	var value string
+	switch {
+	case rule.Metric == "weather_code":
+		value = weathercode.Description(int(a.Value))
+	case strings.HasPrefix(rule.Metric, "temperature"):
+		value = fmt.Sprintf("%.0f%s", a.Value, units.Temperature)
+	case strings.HasPrefix(rule.Metric, "wind"):
+		value = fmt.Sprintf("%.0f %s", a.Value, units.WindSpeed)
+	case strings.HasPrefix(rule.Metric, "precipitation_probability"):
+		value = fmt.Sprintf("%.0f%%", a.Value)
+	default:
+		value = fmt.Sprintf("%.0f", a.Value)
+	}
+	return fmt.Sprintf("%s: %s (%s)", rule.Name, value, when)
+}
+
+// DefaultRules returns the rules used when none are configured.
+// The thresholds are adjusted to the configured units.
+func DefaultRules(temperatureUnit, windSpeedUnit string) []Rule {
+	freezing := 0.0
+	if temperatureUnit == "fahrenheit" {
+		freezing = 32
+	}
+	gusts := map[string]float64{"kmh": 60, "ms": 17, "mph": 37, "kn": 32}[windSpeedUnit]
+	rules := []Rule{
+		{
+			Name:     "Thunderstorm",
+			Metric:   "weather_code",
+			Operator: ">=",
+			Value:    95,
+			Within:   6 * time.Hour,
+			Severity: Severe,
+		},
+		{
+			Name:     "Strong gusts",
+			Metric:   "wind_gusts",
+			Operator: ">",
+			Value:    gusts,
+			Within:   24 * time.Hour,
+			Severity: Warning,
+		},
+		{
+			Name:      "Frost overnight",
+			Metric:    "temperature",
+			Operator:  "<",
+			Value:     freezing,
+			Within:    24 * time.Hour,
+			NightOnly: true,
+			Severity:  Info,
+		},
+	}
+	return rules
+}
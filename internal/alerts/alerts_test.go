@@ -0,0 +1,208 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ErikKalkoken/weatherapp/internal/forecast"
+)
+
+var testNow = time.Date(2024, 11, 20, 12, 0, 0, 0, time.UTC)
+
+// makeHourly returns a forecast with the current hour at testNow followed by one hour per temperature.
+// The hours from 18:00 to 6:00 are at night.
+func makeHourly(temperatures ...float64) forecast.Result {
+	var hours []forecast.ForecastHour
+	for i, v := range temperatures {
+		t := testNow.Add(time.Duration(i) * time.Hour)
+		hours = append(hours, forecast.ForecastHour{
+			Time:          t,
+			IsDay:         t.Hour() >= 6 && t.Hour() < 18,
+			Temperature2m: v,
+		})
+	}
+	r := forecast.Result{Current: hours[0], Hourly: hours[1:]}
+	r.Units.Temperature = "°C"
+	return r
+}
+
+func hour(i int) time.Time {
+	return testNow.Add(time.Duration(i) * time.Hour)
+}
+
+func TestEvaluateHourly(t *testing.T) {
+	cases := []struct {
+		name      string
+		rule      Rule
+		values    []float64
+		want      bool
+		start     time.Time
+		end       time.Time
+		wantValue float64
+	}{
+		{"<", Rule{Operator: "<", Value: 0}, []float64{1, 0, -1, -3, 2}, true, hour(2), hour(3), -3},
+		{"<=", Rule{Operator: "<=", Value: 0}, []float64{1, 0, -1, 2}, true, hour(1), hour(2), -1},
+		{">", Rule{Operator: ">", Value: 30}, []float64{30, 31, 35, 32}, true, hour(1), hour(3), 35},
+		{">=", Rule{Operator: ">=", Value: 30}, []float64{29, 30, 29}, true, hour(1), hour(1), 30},
+		{"==", Rule{Operator: "==", Value: 5}, []float64{4, 5, 6, 5}, true, hour(1), hour(3), 5},
+		{"no match", Rule{Operator: ">", Value: 30}, []float64{20, 25, 30}, false, time.Time{}, time.Time{}, 0},
+		{"current hour", Rule{Operator: ">", Value: 30}, []float64{31, 20}, true, hour(0), hour(0), 31},
+		{"within", Rule{Operator: ">", Value: 30, Within: 2 * time.Hour}, []float64{20, 31, 32, 40}, true, hour(1), hour(2), 32},
+		{"after within", Rule{Operator: ">", Value: 30, Within: 2 * time.Hour}, []float64{20, 20, 20, 40}, false, time.Time{}, time.Time{}, 0},
+		{"night only", Rule{Operator: "<", Value: 0, NightOnly: true}, []float64{-5, -4, -3, -2, -1, -1, -1, 5}, true, hour(6), hour(6), -1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.rule.Name = "Test"
+			tc.rule.Metric = "temperature"
+			got := Evaluate([]Rule{tc.rule}, makeHourly(tc.values...), testNow)
+			if !tc.want {
+				if len(got) != 0 {
+					t.Errorf("got %+v, want no alert", got)
+				}
+				return
+			}
+			if len(got) != 1 {
+				t.Fatalf("got %d alerts, want 1", len(got))
+			}
+			a := got[0]
+			if !a.Start.Equal(tc.start) || !a.End.Equal(tc.end) || a.Value != tc.wantValue {
+				t.Errorf("got %s - %s with %v, want %s - %s with %v", a.Start, a.End, a.Value, tc.start, tc.end, tc.wantValue)
+			}
+		})
+	}
+}
+
+func TestEvaluateDaily(t *testing.T) {
+	r := forecast.Result{Current: forecast.ForecastHour{Time: testNow}}
+	for i, v := range []float64{5, -2, -6, 3} {
+		r.Daily = append(r.Daily, forecast.ForecastDay{Time: testNow.AddDate(0, 0, i), Temperature2mMin: v})
+	}
+	cases := []struct {
+		name      string
+		rule      Rule
+		want      bool
+		start     time.Time
+		end       time.Time
+		wantValue float64
+	}{
+		{"match", Rule{Metric: "temperature_min", Operator: "<", Value: 0}, true, testNow.AddDate(0, 0, 1), testNow.AddDate(0, 0, 2), -6},
+		{"within", Rule{Metric: "temperature_min", Operator: "<", Value: 0, Within: 36 * time.Hour}, true, testNow.AddDate(0, 0, 1), testNow.AddDate(0, 0, 1), -2},
+		{"no match", Rule{Metric: "temperature_min", Operator: "<", Value: -10}, false, time.Time{}, time.Time{}, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.rule.Name = "Test"
+			tc.rule.Daily = true
+			got := Evaluate([]Rule{tc.rule}, r, testNow)
+			if !tc.want {
+				if len(got) != 0 {
+					t.Errorf("got %+v, want no alert", got)
+				}
+				return
+			}
+			if len(got) != 1 {
+				t.Fatalf("got %d alerts, want 1", len(got))
+			}
+			a := got[0]
+			if !a.Start.Equal(tc.start) || !a.End.Equal(tc.end) || a.Value != tc.wantValue {
+				t.Errorf("got %s - %s with %v, want %s - %s with %v", a.Start, a.End, a.Value, tc.start, tc.end, tc.wantValue)
+			}
+		})
+	}
+}
+
+func TestEvaluateOrder(t *testing.T) {
+	rules := []Rule{
+		{Name: "info", Metric: "temperature", Operator: ">", Value: 0, Severity: Info},
+		{Name: "warning later", Metric: "temperature", Operator: ">", Value: 20, Severity: Warning},
+		{Name: "severe", Metric: "temperature", Operator: ">", Value: 30, Severity: Severe},
+		{Name: "warning", Metric: "temperature", Operator: ">", Value: 10, Severity: Warning},
+	}
+	got := Evaluate(rules, makeHourly(5, 15, 25, 35), testNow)
+	var names []string
+	for _, a := range got {
+		names = append(names, a.Name)
+	}
+	want := []string{"severe", "warning", "warning later", "info"}
+	if len(names) != len(want) {
+		t.Fatalf("got %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("got %v, want %v", names, want)
+		}
+	}
+}
+
+func TestSeverityUnmarshalText(t *testing.T) {
+	for _, s := range []Severity{Info, Warning, Severe} {
+		var got Severity
+		if err := got.UnmarshalText([]byte(s.String())); err != nil {
+			t.Fatal(err)
+		}
+		if got != s {
+			t.Errorf("got %v, want %v", got, s)
+		}
+	}
+	var s Severity
+	if err := s.UnmarshalText([]byte("critical")); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name string
+		rule Rule
+		ok   bool
+	}{
+		{"hourly", Rule{Metric: "temperature", Operator: "<"}, true},
+		{"daily", Rule{Metric: "temperature_max", Operator: ">", Daily: true}, true},
+		{"invalid hourly metric", Rule{Metric: "temperature_max", Operator: ">"}, false},
+		{"invalid daily metric", Rule{Metric: "temperature", Operator: ">", Daily: true}, false},
+		{"night only daily", Rule{Metric: "temperature_min", Operator: "<", Daily: true, NightOnly: true}, false},
+		{"invalid operator", Rule{Metric: "temperature", Operator: "!="}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rule.Validate()
+			if tc.ok && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if !tc.ok && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestDefaultRules(t *testing.T) {
+	cases := []struct {
+		temperatureUnit, windSpeedUnit string
+		freezing, gusts                float64
+	}{
+		{"celsius", "kmh", 0, 60},
+		{"fahrenheit", "kmh", 32, 60},
+		{"celsius", "ms", 0, 17},
+		{"celsius", "mph", 0, 37},
+		{"fahrenheit", "kn", 32, 32},
+	}
+	for _, tc := range cases {
+		t.Run(tc.temperatureUnit+" "+tc.windSpeedUnit, func(t *testing.T) {
+			thresholds := map[string]float64{}
+			for _, r := range DefaultRules(tc.temperatureUnit, tc.windSpeedUnit) {
+				if err := r.Validate(); err != nil {
+					t.Fatal(err)
+				}
+				thresholds[r.Metric] = r.Value
+			}
+			if got := thresholds["temperature"]; got != tc.freezing {
+				t.Errorf("freezing: got %v, want %v", got, tc.freezing)
+			}
+			if got := thresholds["wind_gusts"]; got != tc.gusts {
+				t.Errorf("gusts: got %v, want %v", got, tc.gusts)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Package apierror defines the errors returned by the clients for web APIs, e.g. the forecast and location clients.
+//
+// Each kind of error has a sentinel error for use with [errors.Is]
+// and an error type with details for use with [errors.As].
+package apierror
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNetwork        = errors.New("network failure")
+	ErrHTTPStatus     = errors.New("HTTP error")
+	ErrAPI            = errors.New("API error")
+	ErrMissingField   = errors.New("missing field")
+	ErrMalformedValue = errors.New("malformed value")
+	ErrNotFound       = errors.New("not found")
+)
+
+// NetworkError is a failed request, e.g. because there is no connection or it timed out.
+type NetworkError struct {
+	API string // name of the API
+	Err error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("making request to %s: %s", e.API, e.Err)
+}
+
+func (e *NetworkError) Is(target error) bool { return target == ErrNetwork }
+func (e *NetworkError) Unwrap() error        { return e.Err }
+
+// HTTPStatusError is a response with an HTTP error status.
+type HTTPStatusError struct {
+	API        string
+	StatusCode int
+	Status     string // e.g. "503 Service Unavailable"
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("request to %s failed: %s", e.API, e.Status)
+}
+
+func (e *HTTPStatusError) Is(target error) bool { return target == ErrHTTPStatus }
+
+// APIError is an error reported by an API, e.g. for invalid parameters.
+type APIError struct {
+	API    string
+	Reason string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("error from %s: %s", e.API, e.Reason)
+}
+
+func (e *APIError) Is(target error) bool { return target == ErrAPI }
+
+// MissingFieldError is a response which does not contain an expected field.
+type MissingFieldError struct {
+	Field string // e.g. "hourly.temperature_2m"
+}
+
+func (e *MissingFieldError) Error() string {
+	return fmt.Sprintf("missing field in response: %s", e.Field)
+}
+
+func (e *MissingFieldError) Is(target error) bool { return target == ErrMissingField }
+
+// MalformedValueError is a response with a field, which has an unexpected value.
+type MalformedValueError struct {
+	Field string // "response" when the whole response is malformed
+	Value any    // optional
+	Err   error  // optional
+}
+
+func (e *MalformedValueError) Error() string {
+	s := "malformed value in response for " + e.Field
+	if e.Value != nil {
+		s += fmt.Sprintf(": %v", e.Value)
+	}
+	if e.Err != nil {
+		s += ": " + e.Err.Error()
+	}
+	return s
+}
+
+func (e *MalformedValueError) Is(target error) bool { return target == ErrMalformedValue }
+func (e *MalformedValueError) Unwrap() error        { return e.Err }
@@ -0,0 +1,146 @@
+// Package archive provides the observed weather of past days.
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ErikKalkoken/weatherapp/internal/apierror"
+	"github.com/ErikKalkoken/weatherapp/internal/forecast"
+)
+
+const (
+	apiName    = "Open-Meteo historical weather API" // name of the API in errors
+	dateLayout = "2006-01-02"
+)
+
+// Client is a client for the Open-Meteo historical weather API.
+type Client struct {
+	BaseURL           string
+	PrecipitationUnit string // mm or inch
+	TemperatureUnit   string // celsius or fahrenheit
+	WindSpeedUnit     string // kmh, ms, mph or kn
+
+	httpClient *http.Client
+}
+
+// NewClient returns a new client with default settings.
+func NewClient(httpClient *http.Client) *Client {
+	c := &Client{
+		BaseURL:           "https://archive-api.open-meteo.com/v1/archive",
+		PrecipitationUnit: "mm",
+		TemperatureUnit:   "celsius",
+		WindSpeedUnit:     "kmh",
+		httpClient:        httpClient,
+	}
+	return c
+}
+
+// Get returns the observed weather for a location for all days from start to end (inclusive).
+// The archive lags a few days behind. Days without data yet are omitted.
+func (c *Client) Get(lat float64, lon float64, start, end time.Time) ([]forecast.ForecastDay, error) {
+	if end.Before(start) {
+		return nil, fmt.Errorf("invalid date range: %s - %s", start.Format(dateLayout), end.Format(dateLayout))
+	}
+	v := url.Values{}
+	v.Add("latitude", fmt.Sprint(lat))
+	v.Add("longitude", fmt.Sprint(lon))
+	v.Add("timezone", "GMT")
+	v.Add("start_date", start.Format(dateLayout))
+	v.Add("end_date", end.Format(dateLayout))
+	v.Add("temperature_unit", c.TemperatureUnit)
+	v.Add("wind_speed_unit", c.WindSpeedUnit)
+	v.Add("precipitation_unit", c.PrecipitationUnit)
+	v.Add("daily", "temperature_2m_max,temperature_2m_min,weather_code,wind_gusts_10m_max,precipitation_sum,rain_sum,snowfall_sum,precipitation_hours")
+	resp, err := c.httpClient.Get(c.BaseURL + "?" + v.Encode())
+	if err != nil {
+		return nil, &apierror.NetworkError{API: apiName, Err: err}
+	}
+	defer resp.Body.Close()
+
+	var response archiveResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		if resp.StatusCode >= 400 {
+			return nil, &apierror.HTTPStatusError{API: apiName, StatusCode: resp.StatusCode, Status: resp.Status}
+		}
+		return nil, &apierror.MalformedValueError{Field: "response", Err: err}
+	}
+	if response.Error {
+		return nil, &apierror.APIError{API: apiName, Reason: response.Reason}
+	}
+	return parseDaily(response)
+}
+
+// archiveResponse is the response from the archive API.
+// Values are null for days, which are not yet in the archive.
+type archiveResponse struct {
+	Error  bool   `json:"error"`
+	Reason string `json:"reason"`
+	Daily  struct {
+		Time               []string   `json:"time"`
+		PrecipitationHours []*float64 `json:"precipitation_hours"`
+		PrecipitationSum   []*float64 `json:"precipitation_sum"`
+		RainSum            []*float64 `json:"rain_sum"`
+		SnowfallSum        []*float64 `json:"snowfall_sum"`
+		Temperature2mMax   []*float64 `json:"temperature_2m_max"`
+		Temperature2mMin   []*float64 `json:"temperature_2m_min"`
+		WeatherCode        []*float64 `json:"weather_code"`
+		WindGusts10mMax    []*float64 `json:"wind_gusts_10m_max"`
+	} `json:"daily"`
+}
+
+func parseDaily(response archiveResponse) ([]forecast.ForecastDay, error) {
+	d := response.Daily
+	n := len(d.Time)
+	for name, vv := range map[string][]*float64{
+		"precipitation_hours": d.PrecipitationHours,
+		"precipitation_sum":   d.PrecipitationSum,
+		"rain_sum":            d.RainSum,
+		"snowfall_sum":        d.SnowfallSum,
+		"temperature_2m_max":  d.Temperature2mMax,
+		"temperature_2m_min":  d.Temperature2mMin,
+		"weather_code":        d.WeatherCode,
+		"wind_gusts_10m_max":  d.WindGusts10mMax,
+	} {
+		if vv == nil {
+			return nil, &apierror.MissingFieldError{Field: "daily." + name}
+		}
+		if len(vv) != n {
+			return nil, &apierror.MalformedValueError{
+				Field: "daily." + name,
+				Err:   fmt.Errorf("expected %d values, got %d", n, len(vv)),
+			}
+		}
+	}
+	days := make([]forecast.ForecastDay, 0, n)
+	for i, v := range d.Time {
+		if d.Temperature2mMax[i] == nil || d.Temperature2mMin[i] == nil || d.WeatherCode[i] == nil || d.WindGusts10mMax[i] == nil {
+			continue
+		}
+		value := func(v *float64) float64 {
+			if v == nil {
+				return 0
+			}
+			return *v
+		}
+		t, err := time.Parse(dateLayout, v)
+		if err != nil {
+			return nil, &apierror.MalformedValueError{Field: "daily.time", Value: v, Err: err}
+		}
+		days = append(days, forecast.ForecastDay{
+			PrecipitationHours: value(d.PrecipitationHours[i]),
+			PrecipitationSum:   value(d.PrecipitationSum[i]),
+			RainSum:            value(d.RainSum[i]),
+			SnowfallSum:        value(d.SnowfallSum[i]),
+			Temperature2mMax:   *d.Temperature2mMax[i],
+			Temperature2mMin:   *d.Temperature2mMin[i],
+			Time:               t.UTC(),
+			WeatherCode:        int(*d.WeatherCode[i]),
+			WindGusts10mMax:    *d.WindGusts10mMax[i],
+		})
+	}
+	return days, nil
+}
@@ -0,0 +1,433 @@
+// Package cap reads official weather warnings in the Common Alerting Protocol (CAP 1.2) format.
+//
+// Feeds can be single CAP alerts or Atom feeds with entries linking to CAP alerts.
+// Feeds can be fetched from URLs or read from local files, e.g. for testing.
+package cap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ErikKalkoken/weatherapp/internal/location"
+)
+
+// Alert is a CAP alert message.
+type Alert struct {
+	Identifier string `xml:"identifier"`
+	Sender     string `xml:"sender"`
+	Sent       string `xml:"sent"`
+	Status     string `xml:"status"`     // e.g. Actual or Test
+	MsgType    string `xml:"msgType"`    // e.g. Alert, Update or Cancel
+	References string `xml:"references"` // space separated list of "sender,identifier,sent" of earlier alerts
+	Info       []Info `xml:"info"`
+}
+
+// Info describes an event of an alert.
+type Info struct {
+	Language    string `xml:"language"`
+	Category    string `xml:"category"`
+	Event       string `xml:"event"`
+	Urgency     string `xml:"urgency"`
+	Severity    string `xml:"severity"` // Extreme, Severe, Moderate, Minor or Unknown
+	Certainty   string `xml:"certainty"`
+	Effective   string `xml:"effective"`
+	Onset       string `xml:"onset"`
+	Expires     string `xml:"expires"`
+	SenderName  string `xml:"senderName"`
+	Headline    string `xml:"headline"`
+	Description string `xml:"description"`
+	Instruction string `xml:"instruction"`
+	Area        []Area `xml:"area"`
+}
+
+// Area is the area affected by an event.
+type Area struct {
+	AreaDesc string   `xml:"areaDesc"`
+	Polygon  []string `xml:"polygon"` // space separated list of "lat,lon" pairs
+	Circle   []string `xml:"circle"`  // "lat,lon radius" with radius in km
+}
+
+// Warning is an active warning for a location.
+type Warning struct {
+	Area        string    `json:"area"`
+	Description string    `json:"description"`
+	Event       string    `json:"event"`
+	Headline    string    `json:"headline"`
+	Instruction string    `json:"instruction"`
+	Sender      string    `json:"sender"`
+	Severity    string    `json:"severity"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"` // zero when open ended
+}
+
+// Parse parses a CAP alert.
+func Parse(r io.Reader) (Alert, error) {
+	var a Alert
+	if err := xml.NewDecoder(r).Decode(&a); err != nil {
+		return Alert{}, fmt.Errorf("parsing CAP alert: %w", err)
+	}
+	return a, nil
+}
+
+type atomFeed struct {
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID      string `xml:"id"`
+	Updated string `xml:"updated"`
+	Links   []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"link"`
+}
+
+// ref returns the reference to the CAP alert of an entry.
+// Links to CAP alerts are preferred over other alternate links. Falls back to the entry's ID.
+func (e atomEntry) ref() string {
+	for _, l := range e.Links {
+		if l.Type == "application/cap+xml" {
+			return l.Href
+		}
+	}
+	for _, l := range e.Links {
+		if l.Rel == "alternate" || l.Rel == "" {
+			return l.Href
+		}
+	}
+	return e.ID
+}
+
+// cachedEntry is an alert fetched for an entry of an Atom feed.
+type cachedEntry struct {
+	updated string
+	alert   Alert
+}
+
+// Feed is a source of CAP alerts.
+type Feed struct {
+	URL string // URL or path of a local file
+
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	cache map[string]cachedEntry // alerts of Atom feed entries by entry ID
+}
+
+func NewFeed(httpClient *http.Client, url string) *Feed {
+	f := &Feed{URL: url, httpClient: httpClient, cache: make(map[string]cachedEntry)}
+	return f
+}
+
+// Fetch returns all alerts from the feed.
+func (f *Feed) Fetch() ([]Alert, error) {
+	data, err := f.read(f.URL)
+	if err != nil {
+		return nil, err
+	}
+	root, err := rootElement(data)
+	if err != nil {
+		return nil, err
+	}
+	switch root {
+	case "alert":
+		a, err := Parse(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return []Alert{a}, nil
+	case "feed":
+		var feed atomFeed
+		if err := xml.Unmarshal(data, &feed); err != nil {
+			return nil, fmt.Errorf("parsing Atom feed: %w", err)
+		}
+		return f.fetchEntries(feed.Entries), nil
+	}
+	return nil, fmt.Errorf("unsupported feed: root element %s", root)
+}
+
+// fetchEntries returns the alerts of the entries of an Atom feed.
+// Alerts of entries which have not been updated since the last fetch are taken from the cache.
+// Entries which can not be fetched or parsed are skipped.
+func (f *Feed) fetchEntries(entries []atomEntry) []Alert {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	alerts := make([]Alert, 0)
+	cache := make(map[string]cachedEntry)
+	for _, e := range entries {
+		if c, ok := f.cache[e.ID]; ok && e.ID != "" && e.Updated != "" && c.updated == e.Updated {
+			alerts = append(alerts, c.alert)
+			cache[e.ID] = c
+			continue
+		}
+		ref := resolve(f.URL, e.ref())
+		data, err := f.read(ref)
+		if err != nil {
+			log.Printf("ERROR: skipping CAP feed entry %s: %s", e.ID, err)
+			continue
+		}
+		a, err := Parse(bytes.NewReader(data))
+		if err != nil {
+			log.Printf("ERROR: skipping CAP feed entry %s: %s", e.ID, err)
+			continue
+		}
+		alerts = append(alerts, a)
+		if e.ID != "" {
+			cache[e.ID] = cachedEntry{updated: e.Updated, alert: a}
+		}
+	}
+	f.cache = cache
+	return alerts
+}
+
+// read returns the content of a URL or local file.
+func (f *Feed) read(ref string) ([]byte, error) {
+	if !isURL(ref) {
+		return os.ReadFile(strings.TrimPrefix(ref, "file://"))
+	}
+	resp, err := f.httpClient.Get(ref)
+	if err != nil {
+		return nil, fmt.Errorf("making request to CAP feed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("making request to CAP feed %s: %s", ref, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func isURL(ref string) bool {
+	return strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://")
+}
+
+// resolve resolves a reference relative to the feed's URL or path.
+func resolve(base, ref string) string {
+	if !isURL(base) {
+		if isURL(ref) || filepath.IsAbs(ref) {
+			return ref
+		}
+		return filepath.Join(filepath.Dir(strings.TrimPrefix(base, "file://")), ref)
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	r, err := b.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return r.String()
+}
+
+func rootElement(data []byte) (string, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		t, err := d.Token()
+		if err != nil {
+			return "", fmt.Errorf("parsing feed: %w", err)
+		}
+		if e, ok := t.(xml.StartElement); ok {
+			return e.Name.Local, nil
+		}
+	}
+}
+
+// Active returns the warnings from alerts which are active at a location,
+// i.e. which have started and not yet expired.
+// Alerts which have been updated or cancelled by other alerts are ignored.
+func Active(alerts []Alert, loc location.Location, now time.Time) []Warning {
+	replaced := make(map[string]bool)
+	for _, a := range alerts {
+		if a.MsgType != "Update" && a.MsgType != "Cancel" {
+			continue
+		}
+		for _, r := range strings.Fields(a.References) {
+			sender, identifier, _ := strings.Cut(r, ",")
+			identifier, _, _ = strings.Cut(identifier, ",")
+			replaced[sender+","+identifier] = true
+		}
+	}
+	warnings := make([]Warning, 0)
+	for _, a := range alerts {
+		if a.Status != "Actual" || a.MsgType == "Cancel" || replaced[a.Sender+","+a.Identifier] {
+			continue
+		}
+		for _, info := range a.Info {
+			start := parseTime(info.Onset)
+			if start.IsZero() {
+				start = parseTime(info.Effective)
+			}
+			if start.IsZero() {
+				start = parseTime(a.Sent)
+			}
+			end := parseTime(info.Expires)
+			if now.Before(start) || !end.IsZero() && !now.Before(end) {
+				continue
+			}
+			area, ok := matchArea(info.Area, loc)
+			if !ok {
+				continue
+			}
+			sender := info.SenderName
+			if sender == "" {
+				sender = a.Sender
+			}
+			warnings = append(warnings, Warning{
+				Area:        area,
+				Description: strings.TrimSpace(info.Description),
+				End:         end,
+				Event:       info.Event,
+				Headline:    info.Headline,
+				Instruction: strings.TrimSpace(info.Instruction),
+				Sender:      sender,
+				Severity:    info.Severity,
+				Start:       start,
+			})
+		}
+	}
+	return warnings
+}
+
+func parseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// matchArea returns the description of the first area containing a location.
+// Areas without polygons and circles match when their description contains the location's city as a whole word.
+func matchArea(areas []Area, loc location.Location) (string, bool) {
+	for _, a := range areas {
+		if len(a.Polygon) == 0 && len(a.Circle) == 0 {
+			if containsWord(a.AreaDesc, loc.City) {
+				return a.AreaDesc, true
+			}
+			continue
+		}
+		for _, p := range a.Polygon {
+			if polygonContains(parsePoints(p), loc.Latitude, loc.Longitude) {
+				return a.AreaDesc, true
+			}
+		}
+		for _, c := range a.Circle {
+			if circleContains(c, loc.Latitude, loc.Longitude) {
+				return a.AreaDesc, true
+			}
+		}
+	}
+	return "", false
+}
+
+// containsWord reports whether s contains word, ignoring case.
+// The word must not be part of a longer word, e.g. "Ulm" is not contained in "Ulmen".
+func containsWord(s, word string) bool {
+	s, word = strings.ToLower(s), strings.ToLower(word)
+	if word == "" {
+		return false
+	}
+	for i := 0; ; {
+		j := strings.Index(s[i:], word)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(word)
+		before, _ := utf8.DecodeLastRuneInString(s[:start])
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+type point struct {
+	lat, lon float64
+}
+
+func parsePoints(s string) []point {
+	var points []point
+	for _, pair := range strings.Fields(s) {
+		p, ok := parsePoint(pair)
+		if !ok {
+			return nil
+		}
+		points = append(points, p)
+	}
+	return points
+}
+
+func parsePoint(s string) (point, bool) {
+	lat, lon, ok := strings.Cut(s, ",")
+	if !ok {
+		return point{}, false
+	}
+	x, err1 := strconv.ParseFloat(lat, 64)
+	y, err2 := strconv.ParseFloat(lon, 64)
+	if err1 != nil || err2 != nil {
+		return point{}, false
+	}
+	return point{x, y}, true
+}
+
+// polygonContains reports whether a point is inside a polygon using the ray casting algorithm.
+func polygonContains(polygon []point, lat, lon float64) bool {
+	if len(polygon) < 3 {
+		return false
+	}
+	var inside bool
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.lat > lat) != (b.lat > lat) && lon < (b.lon-a.lon)*(lat-a.lat)/(b.lat-a.lat)+a.lon {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// circleContains reports whether a point is inside a CAP circle.
+func circleContains(circle string, lat, lon float64) bool {
+	center, radius, ok := strings.Cut(strings.TrimSpace(circle), " ")
+	if !ok {
+		return false
+	}
+	c, ok := parsePoint(center)
+	if !ok {
+		return false
+	}
+	r, err := strconv.ParseFloat(strings.TrimSpace(radius), 64)
+	if err != nil {
+		return false
+	}
+	return distance(c.lat, c.lon, lat, lon) <= r
+}
+
+// distance returns the great-circle distance in km between two points.
+func distance(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371.0
+	toRad := func(x float64) float64 { return x * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
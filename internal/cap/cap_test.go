@@ -0,0 +1,262 @@
+package cap
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ErikKalkoken/weatherapp/internal/location"
+)
+
+var berlin = location.Location{City: "Berlin", Latitude: 52.52, Longitude: 13.405}
+
+// feedServer serves the files in testdata and records the requested paths.
+type feedServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	feed     string // content of feed.xml
+	requests []string
+}
+
+func newFeedServer(t *testing.T) *feedServer {
+	data, err := os.ReadFile("testdata/feed.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &feedServer{feed: string(data)}
+	files := http.FileServer(http.Dir("testdata"))
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests = append(s.requests, r.URL.Path)
+		if r.URL.Path == "/feed.xml" {
+			w.Header().Set("Content-Type", "application/atom+xml")
+			w.Write([]byte(s.feed))
+			return
+		}
+		files.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// reset returns the recorded requests and clears them.
+func (s *feedServer) reset() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.requests
+	s.requests = nil
+	return r
+}
+
+func identifiers(alerts []Alert) []string {
+	var ids []string
+	for _, a := range alerts {
+		ids = append(ids, a.Identifier)
+	}
+	return ids
+}
+
+func TestFeedFetchAtom(t *testing.T) {
+	s := newFeedServer(t)
+	f := NewFeed(s.Client(), s.URL+"/feed.xml")
+	alerts, err := f.Fetch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := identifiers(alerts), []string{"storm-1", "storm-2", "frost-1", "frost-2"}; !slices.Equal(got, want) {
+		t.Errorf("got alerts %v, want %v", got, want)
+	}
+	requests := s.reset()
+	if slices.Contains(requests, "/storm-1.html") || slices.Contains(requests, "/storm-2.html") {
+		t.Errorf("fetched HTML links instead of CAP links: %v", requests)
+	}
+	if !slices.Contains(requests, "/missing.xml") || !slices.Contains(requests, "/invalid.xml") {
+		t.Errorf("bad entries not fetched: %v", requests)
+	}
+	t.Run("unchanged entries are cached", func(t *testing.T) {
+		alerts, err := f.Fetch()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(alerts) != 4 {
+			t.Errorf("got %d alerts, want 4", len(alerts))
+		}
+		// bad entries are fetched again
+		if got, want := s.reset(), []string{"/feed.xml", "/missing.xml", "/invalid.xml"}; !slices.Equal(got, want) {
+			t.Errorf("got requests %v, want %v", got, want)
+		}
+	})
+	t.Run("updated entries are fetched again", func(t *testing.T) {
+		s.mu.Lock()
+		s.feed = strings.Replace(s.feed, "<updated>2024-11-20T06:00:00Z</updated>", "<updated>2024-11-20T11:00:00Z</updated>", 1)
+		s.mu.Unlock()
+		if _, err := f.Fetch(); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := s.reset(), []string{"/feed.xml", "/frost-1.xml", "/missing.xml", "/invalid.xml"}; !slices.Equal(got, want) {
+			t.Errorf("got requests %v, want %v", got, want)
+		}
+	})
+}
+
+func TestFeedFetchAlert(t *testing.T) {
+	s := newFeedServer(t)
+	f := NewFeed(s.Client(), s.URL+"/storm-1.xml")
+	alerts, err := f.Fetch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := identifiers(alerts); !slices.Equal(got, []string{"storm-1"}) {
+		t.Errorf("got alerts %v", got)
+	}
+}
+
+func TestFeedFetchErrors(t *testing.T) {
+	s := newFeedServer(t)
+	for _, name := range []string{"missing.xml", "invalid.xml", "storm-1.html"} {
+		t.Run(name, func(t *testing.T) {
+			f := NewFeed(s.Client(), s.URL+"/"+name)
+			if _, err := f.Fetch(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestFeedFetchFile(t *testing.T) {
+	f := NewFeed(http.DefaultClient, "testdata/feed.xml")
+	alerts, err := f.Fetch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(alerts); got != 4 {
+		t.Errorf("got %d alerts, want 4", got)
+	}
+}
+
+func TestActive(t *testing.T) {
+	f := NewFeed(http.DefaultClient, "testdata/feed.xml")
+	alerts, err := f.Fetch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 11, 20, 15, 0, 0, 0, time.UTC)
+	t.Run("updated and cancelled alerts are dropped", func(t *testing.T) {
+		warnings := Active(alerts, berlin, now)
+		if len(warnings) != 1 {
+			t.Fatalf("got %d warnings, want 1: %+v", len(warnings), warnings)
+		}
+		w := warnings[0]
+		if w.Event != "Storm" || w.Severity != "Severe" || w.Area != "Berlin" || w.Sender != "Example Weather Service" {
+			t.Errorf("got %+v", w)
+		}
+		if want := time.Date(2024, 11, 20, 12, 0, 0, 0, time.UTC); !w.Start.Equal(want) {
+			t.Errorf("got start %v, want %v", w.Start, want)
+		}
+	})
+	t.Run("expired", func(t *testing.T) {
+		if warnings := Active(alerts, berlin, now.Add(24*time.Hour)); len(warnings) != 0 {
+			t.Errorf("got %d warnings, want 0", len(warnings))
+		}
+	})
+	t.Run("not yet started", func(t *testing.T) {
+		if warnings := Active(alerts, berlin, now.Add(-4*time.Hour)); len(warnings) != 0 {
+			t.Errorf("got %d warnings, want 0", len(warnings))
+		}
+	})
+	t.Run("at onset", func(t *testing.T) {
+		if warnings := Active(alerts, berlin, time.Date(2024, 11, 20, 12, 0, 0, 0, time.UTC)); len(warnings) != 1 {
+			t.Errorf("got %d warnings, want 1", len(warnings))
+		}
+	})
+	t.Run("at expiry", func(t *testing.T) {
+		if warnings := Active(alerts, berlin, time.Date(2024, 11, 21, 6, 0, 0, 0, time.UTC)); len(warnings) != 0 {
+			t.Errorf("got %d warnings, want 0", len(warnings))
+		}
+	})
+	t.Run("outside of area", func(t *testing.T) {
+		hamburg := location.Location{City: "Hamburg", Latitude: 53.55, Longitude: 9.99}
+		if warnings := Active(alerts, hamburg, now); len(warnings) != 0 {
+			t.Errorf("got %d warnings, want 0", len(warnings))
+		}
+	})
+}
+
+func TestMatchArea(t *testing.T) {
+	cases := []struct {
+		name string
+		area Area
+		want bool
+	}{
+		{"polygon", Area{Polygon: []string{"52.3,13.0 52.7,13.0 52.7,13.8 52.3,13.8 52.3,13.0"}}, true},
+		{"polygon outside", Area{Polygon: []string{"50,10 51,10 51,11 50,11 50,10"}}, false},
+		{"circle", Area{Circle: []string{"52.5,13.4 10"}}, true},
+		{"circle outside", Area{Circle: []string{"48.1,11.6 50"}}, false},
+		{"description", Area{AreaDesc: "City of Berlin"}, true},
+		{"description outside", Area{AreaDesc: "Hamburg"}, false},
+		{"description case", Area{AreaDesc: "STADT BERLIN"}, true},
+		{"description part of word", Area{AreaDesc: "Berlinchen"}, false},
+		{"description with punctuation", Area{AreaDesc: "Brandenburg, Berlin-Mitte"}, true},
+		{"polygon before description", Area{AreaDesc: "Berlin", Polygon: []string{"50,10 51,10 51,11 50,11 50,10"}}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, got := matchArea([]Area{tc.area}, berlin); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestContainsWord(t *testing.T) {
+	cases := []struct {
+		s, word string
+		want    bool
+	}{
+		{"Ulm", "Ulm", true},
+		{"Stadt Ulm", "ulm", true},
+		{"Ulmen", "Ulm", false},
+		{"Neu-Ulm", "Ulm", true},
+		{"Kreis Ulmen, Ulm", "Ulm", true},
+		{"Landkreis Neu-Ulmer Land", "Ulm", false},
+		{"Münster", "Münster", true},
+		{"Bad Münstereifel", "Münster", false},
+		{"Ulm", "", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.s+"/"+tc.word, func(t *testing.T) {
+			if got := containsWord(tc.s, tc.word); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPollerPoll(t *testing.T) {
+	s := newFeedServer(t)
+	p := NewPoller(NewFeed(s.Client(), s.URL+"/feed.xml"), time.Hour)
+	changed, err := p.Poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed {
+		t.Error("first poll: expected change")
+	}
+	changed, err = p.Poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed {
+		t.Error("second poll: expected no change")
+	}
+	if got := p.Warnings(berlin, time.Date(2024, 11, 20, 15, 0, 0, 0, time.UTC)); len(got) != 1 {
+		t.Errorf("got %d warnings, want 1", len(got))
+	}
+}
@@ -0,0 +1,103 @@
+package cap
+
+import (
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ErikKalkoken/weatherapp/internal/location"
+)
+
+// Poller fetches a feed periodically and keeps the latest alerts.
+type Poller struct {
+	// OnChange is called when the alerts in the feed have changed. Must be set before Start.
+	OnChange func()
+
+	feed     *Feed
+	interval time.Duration
+
+	mu      sync.RWMutex
+	alerts  []Alert
+	polled  bool
+	started bool
+
+	done     chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewPoller(feed *Feed, interval time.Duration) *Poller {
+	p := &Poller{
+		done:     make(chan struct{}),
+		feed:     feed,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+	return p
+}
+
+// Start starts polling the feed in the background. The first poll happens immediately.
+func (p *Poller) Start() {
+	p.mu.Lock()
+	p.started = true
+	p.mu.Unlock()
+	go func() {
+		defer close(p.done)
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			changed, err := p.Poll()
+			if err != nil {
+				log.Printf("ERROR: fetching CAP feed: %s", err)
+			} else if changed && p.OnChange != nil {
+				p.OnChange()
+			}
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops polling. Must only be called after Start.
+func (p *Poller) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+	<-p.done
+}
+
+// Poll fetches the feed once and reports whether the alerts have changed.
+func (p *Poller) Poll() (bool, error) {
+	alerts, err := p.feed.Fetch()
+	if err != nil {
+		return false, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	changed := !slices.EqualFunc(p.alerts, alerts, func(a, b Alert) bool {
+		return a.Identifier == b.Identifier
+	})
+	p.alerts = alerts
+	p.polled = true
+	return changed, nil
+}
+
+// Warnings returns the active warnings for a location.
+// When the poller has not been started, the feed is fetched once on the first call.
+func (p *Poller) Warnings(loc location.Location, now time.Time) []Warning {
+	p.mu.RLock()
+	mustPoll := !p.started && !p.polled
+	p.mu.RUnlock()
+	if mustPoll {
+		if _, err := p.Poll(); err != nil {
+			log.Printf("ERROR: fetching CAP feed: %s", err)
+		}
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return Active(p.alerts, loc, now)
+}
@@ -0,0 +1,159 @@
+// Package comfort calculates how the weather feels for people, e.g. the heat index or wind chill.
+//
+// All calculations use temperatures in °C, relative humidity in percent and wind speeds in km/h.
+package comfort
+
+import (
+	"math"
+)
+
+// DewPointCategory is a category of comfort by dew point.
+type DewPointCategory uint
+
+const (
+	Dry DewPointCategory = iota
+	Comfortable
+	Humid
+	Muggy
+	Oppressive
+	Miserable
+)
+
+func (c DewPointCategory) String() string {
+	switch c {
+	case Dry:
+		return "dry"
+	case Comfortable:
+		return "comfortable"
+	case Humid:
+		return "humid"
+	case Muggy:
+		return "muggy"
+	case Oppressive:
+		return "oppressive"
+	}
+	return "miserable"
+}
+
+// Metrics are all comfort metrics for the weather at a point in time.
+type Metrics struct {
+	DewPoint         float64 // °C
+	DewPointCategory DewPointCategory
+	FeelsLike        float64 // °C
+	HeatIndex        float64 // °C
+	Humidex          float64 // dimensionless, comparable to °C
+	WBGT             float64 // °C, approximation for shade
+	WindChill        float64 // °C
+}
+
+// Compute returns all comfort metrics for a temperature t in °C, relative humidity rh in % and wind speed v in km/h.
+func Compute(t, rh, v float64) Metrics {
+	td := DewPoint(t, rh)
+	m := Metrics{
+		DewPoint:         td,
+		DewPointCategory: CategoryForDewPoint(td),
+		FeelsLike:        FeelsLike(t, rh, v),
+		HeatIndex:        HeatIndex(t, rh),
+		Humidex:          Humidex(t, rh),
+		WBGT:             WBGT(t, rh),
+		WindChill:        WindChill(t, v),
+	}
+	return m
+}
+
+// FeelsLike returns the apparent temperature in °C.
+// This is the wind chill in cold and windy conditions, the heat index in hot conditions
+// and the air temperature otherwise.
+func FeelsLike(t, rh, v float64) float64 {
+	if t <= 10 && v > 4.8 {
+		return WindChill(t, v)
+	}
+	if t >= 26.7 {
+		return HeatIndex(t, rh)
+	}
+	return t
+}
+
+// HeatIndex returns the heat index in °C as defined by the US National Weather Service.
+// For example it is 40 °C for 32 °C and 70% relative humidity.
+//
+// The Rothfusz regression is used with its adjustments for low and high humidity.
+// The simple formula of Steadman is used for low heat index values, where the regression is not valid.
+func HeatIndex(t, rh float64) float64 {
+	f := celsiusToFahrenheit(t)
+	hi := 0.5 * (f + 61.0 + (f-68.0)*1.2 + rh*0.094)
+	if (hi+f)/2 < 80 {
+		return fahrenheitToCelsius(hi)
+	}
+	hi = -42.379 + 2.04901523*f + 10.14333127*rh -
+		0.22475541*f*rh - 0.00683783*f*f - 0.05481717*rh*rh +
+		0.00122874*f*f*rh + 0.00085282*f*rh*rh - 0.00000199*f*f*rh*rh
+	if rh < 13 && f >= 80 && f <= 112 {
+		hi -= (13 - rh) / 4 * math.Sqrt((17-math.Abs(f-95))/17)
+	} else if rh > 85 && f >= 80 && f <= 87 {
+		hi += (rh - 85) / 10 * (87 - f) / 5
+	}
+	return fahrenheitToCelsius(hi)
+}
+
+// WindChill returns the wind chill temperature in °C as defined by Environment Canada and the US National Weather Service.
+// For example it is -18 °C for -10 °C and 20 km/h.
+//
+// Wind chill is only defined for temperatures up to 10 °C and wind speeds above 4.8 km/h.
+// Otherwise the air temperature is returned.
+func WindChill(t, v float64) float64 {
+	if t > 10 || v <= 4.8 {
+		return t
+	}
+	p := math.Pow(v, 0.16)
+	return 13.12 + 0.6215*t - 11.37*p + 0.3965*t*p
+}
+
+// DewPoint returns the dew point in °C calculated with the Magnus formula.
+// For example it is 12 °C for 20 °C and 60% relative humidity.
+func DewPoint(t, rh float64) float64 {
+	const a, b = 17.625, 243.04
+	rh = max(rh, 1) // avoid log(0)
+	g := math.Log(rh/100) + a*t/(b+t)
+	return b * g / (a - g)
+}
+
+// Humidex returns the humidex as defined by Environment Canada.
+// For example it is 42 for 30 °C and 74% relative humidity, which is a dew point of 25 °C.
+func Humidex(t, rh float64) float64 {
+	td := DewPoint(t, rh)
+	e := 6.11 * math.Exp(5417.7530*(1/273.16-1/(273.15+td)))
+	return t + 0.5555*(e-10)
+}
+
+// WBGT returns an approximation of the wet-bulb globe temperature in °C for shade as used by the Australian Bureau of Meteorology.
+// For example it is 31 °C for 30 °C and 60% relative humidity.
+func WBGT(t, rh float64) float64 {
+	e := rh / 100 * 6.105 * math.Exp(17.27*t/(237.7+t)) // water vapour pressure in hPa
+	return 0.567*t + 0.393*e + 3.94
+}
+
+// CategoryForDewPoint returns the comfort category for a dew point in °C.
+func CategoryForDewPoint(td float64) DewPointCategory {
+	switch {
+	case td < 10:
+		return Dry
+	case td < 15:
+		return Comfortable
+	case td < 18:
+		return Humid
+	case td < 21:
+		return Muggy
+	case td < 24:
+		return Oppressive
+	}
+	return Miserable
+}
+
+func celsiusToFahrenheit(t float64) float64 {
+	return t*9/5 + 32
+}
+
+func fahrenheitToCelsius(t float64) float64 {
+	return (t - 32) * 5 / 9
+}
@@ -0,0 +1,173 @@
+package comfort
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestHeatIndex(t *testing.T) {
+	// Values from the heat index table of the US National Weather Service in °F.
+	cases := []struct {
+		temperature float64 // °F
+		humidity    float64
+		want        float64 // °F
+	}{
+		{80, 40, 80},
+		{88, 60, 95},
+		{90, 70, 106},
+		{96, 50, 108},
+		{100, 40, 109},
+		{110, 40, 136},
+		// high humidity adjustment
+		{84, 90, 98},
+		{86, 90, 105},
+	}
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%.0f°F %.0f%%", tc.temperature, tc.humidity), func(t *testing.T) {
+			got := celsiusToFahrenheit(HeatIndex(fahrenheitToCelsius(tc.temperature), tc.humidity))
+			if math.Round(got) != tc.want {
+				t.Errorf("got %.1f°F, want %.0f°F", got, tc.want)
+			}
+		})
+	}
+	t.Run("low humidity adjustment", func(t *testing.T) {
+		// Value from the heat index equation of the NWS for 100°F and 10% including the adjustment.
+		// The regression alone would give 94.8°F.
+		got := celsiusToFahrenheit(HeatIndex(fahrenheitToCelsius(100), 10))
+		if math.Abs(got-94.1) > 0.1 {
+			t.Errorf("got %.1f°F, want 94.1°F", got)
+		}
+	})
+	t.Run("simple formula below 80°F", func(t *testing.T) {
+		got := celsiusToFahrenheit(HeatIndex(fahrenheitToCelsius(70), 50))
+		if math.Abs(got-69.1) > 0.1 {
+			t.Errorf("got %.1f°F, want 69.1°F", got)
+		}
+	})
+}
+
+func TestWindChill(t *testing.T) {
+	// Values from the wind chill table of Environment Canada.
+	cases := []struct {
+		temperature float64 // °C
+		wind        float64 // km/h
+		want        float64 // °C
+	}{
+		{5, 5, 4},
+		{0, 10, -3},
+		{-5, 40, -14},
+		{-10, 20, -18},
+		{-20, 30, -33},
+		{-30, 50, -49},
+		{-40, 60, -64},
+		// not defined: air temperature is returned
+		{11, 20, 11},
+		{-5, 4.8, -5},
+		{-5, 0, -5},
+	}
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%.0f°C %.1fkmh", tc.temperature, tc.wind), func(t *testing.T) {
+			got := WindChill(tc.temperature, tc.wind)
+			if math.Round(got) != tc.want {
+				t.Errorf("got %.1f°C, want %.0f°C", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHumidex(t *testing.T) {
+	// Values from the humidex table of Environment Canada.
+	cases := []struct {
+		temperature float64 // °C
+		dewPoint    float64 // °C
+		want        float64
+	}{
+		{25, 20, 33},
+		{30, 15, 34},
+		{30, 25, 42},
+		{35, 25, 47},
+		{40, 20, 48},
+	}
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%.0f°C dew point %.0f°C", tc.temperature, tc.dewPoint), func(t *testing.T) {
+			got := Humidex(tc.temperature, relativeHumidity(tc.temperature, tc.dewPoint))
+			if math.Round(got) != tc.want {
+				t.Errorf("got %.1f, want %.0f", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDewPoint(t *testing.T) {
+	cases := []struct {
+		temperature, humidity, want float64
+	}{
+		{20, 60, 12},
+		{30, 74, 25},
+		{0, 100, 0},
+		{10, 80, 7},
+	}
+	for _, tc := range cases {
+		got := DewPoint(tc.temperature, tc.humidity)
+		if math.Round(got) != tc.want {
+			t.Errorf("DewPoint(%v, %v): got %.1f, want %.0f", tc.temperature, tc.humidity, got, tc.want)
+		}
+	}
+}
+
+func TestWBGT(t *testing.T) {
+	// Values from the approximation of the Australian Bureau of Meteorology.
+	cases := []struct {
+		temperature, humidity, want float64
+	}{
+		{25, 50, 24},
+		{30, 60, 31},
+		{35, 30, 30},
+	}
+	for _, tc := range cases {
+		got := WBGT(tc.temperature, tc.humidity)
+		if math.Round(got) != tc.want {
+			t.Errorf("WBGT(%v, %v): got %.1f, want %.0f", tc.temperature, tc.humidity, got, tc.want)
+		}
+	}
+}
+
+func TestFeelsLike(t *testing.T) {
+	if got, want := FeelsLike(-10, 50, 20), WindChill(-10, 20); got != want {
+		t.Errorf("cold and windy: got %v, want wind chill %v", got, want)
+	}
+	if got, want := FeelsLike(32, 70, 20), HeatIndex(32, 70); got != want {
+		t.Errorf("hot: got %v, want heat index %v", got, want)
+	}
+	if got := FeelsLike(18, 50, 20); got != 18 {
+		t.Errorf("mild: got %v, want 18", got)
+	}
+}
+
+func TestCategoryForDewPoint(t *testing.T) {
+	cases := []struct {
+		dewPoint float64
+		want     DewPointCategory
+	}{
+		{5, Dry},
+		{10, Comfortable},
+		{16, Humid},
+		{20, Muggy},
+		{23, Oppressive},
+		{26, Miserable},
+	}
+	for _, tc := range cases {
+		if got := CategoryForDewPoint(tc.dewPoint); got != tc.want {
+			t.Errorf("%v: got %s, want %s", tc.dewPoint, got, tc.want)
+		}
+	}
+}
+
+// relativeHumidity returns the relative humidity for a temperature and a dew point with the Magnus formula.
+func relativeHumidity(t, td float64) float64 {
+	es := func(t float64) float64 {
+		return math.Exp(17.625 * t / (243.04 + t))
+	}
+	return 100 * es(td) / es(t)
+}
@@ -0,0 +1,365 @@
+// Package config loads the app configuration from flags, environment and a config file.
+//
+// Settings are applied with the following precedence: flags > environment > config file > defaults.
+// Each setting is available as flag (e.g. -refresh-interval), as environment variable
+// (e.g. WEATHERAPP_REFRESH_INTERVAL) and as key in the TOML config file (e.g. refresh_interval).
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/ErikKalkoken/weatherapp/internal/alerts"
+)
+
+const envPrefix = "WEATHERAPP_"
+
+// Config is the configuration of the app.
+type Config struct {
+	City              string        `toml:"city"`
+	Latitude          float64       `toml:"latitude"`
+	Longitude         float64       `toml:"longitude"`
+	TemperatureUnit   string        `toml:"temperature_unit"`
+	WindSpeedUnit     string        `toml:"wind_speed_unit"`
+	PrecipitationUnit string        `toml:"precipitation_unit"`
+	RefreshInterval   time.Duration `toml:"refresh_interval"`
+	Timeout           time.Duration `toml:"timeout"`
+	ForecastDays      int           `toml:"forecast_days"`
+	ForecastURL       string        `toml:"forecast_url"`
+	ArchiveURL        string        `toml:"archive_url"`
+	GeocodingURL      string        `toml:"geocoding_url"`
+	LocationURL       string        `toml:"location_url"`
+	Proxy             string        `toml:"proxy"`
+	CAPFeedURL        string        `toml:"cap_feed_url"`
+	CAPPollInterval   time.Duration `toml:"cap_poll_interval"`
+	Locations         []string      `toml:"locations"`
+	Models            []string      `toml:"models"`
+	History           bool          `toml:"history"`
+	HistoryPath       string        `toml:"history_path"`
+	IconPack          string        `toml:"icon_pack"`
+	IconPacksDir      string        `toml:"icon_packs_dir"`
+	Theme             string        `toml:"theme"`
+	ThemeTint         bool          `toml:"theme_tint"`
+
+	// Alerts are the rules for weather alerts. They can only be set in the config file.
+	Alerts []alerts.Rule `toml:"alerts"`
+
+	hasCoordinates bool
+}
+
+// Default returns the default configuration.
+func Default() Config {
+	c := Config{
+		TemperatureUnit:   "celsius",
+		WindSpeedUnit:     "kmh",
+		PrecipitationUnit: "mm",
+		RefreshInterval:   15 * time.Minute,
+		Timeout:           30 * time.Second,
+		ForecastDays:      10,
+		ForecastURL:       "https://api.open-meteo.com/v1/forecast",
+		ArchiveURL:        "https://archive-api.open-meteo.com/v1/archive",
+		GeocodingURL:      "https://geocoding-api.open-meteo.com/v1/search",
+		LocationURL:       "http://ip-api.com/json/",
+		CAPPollInterval:   5 * time.Minute,
+		Models:            []string{"ecmwf_ifs025", "gfs_seamless", "icon_seamless"},
+		History:           true,
+		Theme:             "auto",
+	}
+	return c
+}
+
+// HasCoordinates reports whether a fixed location has been configured with coordinates.
+func (c Config) HasCoordinates() bool {
+	return c.hasCoordinates
+}
+
+// Validate reports an error when the configuration is invalid.
+func (c Config) Validate() error {
+	if c.Latitude < -90 || c.Latitude > 90 {
+		return fmt.Errorf("invalid latitude: %v", c.Latitude)
+	}
+	if c.Longitude < -180 || c.Longitude > 180 {
+		return fmt.Errorf("invalid longitude: %v", c.Longitude)
+	}
+	if !slices.Contains([]string{"celsius", "fahrenheit"}, c.TemperatureUnit) {
+		return fmt.Errorf("invalid temperature unit: %s", c.TemperatureUnit)
+	}
+	if !slices.Contains([]string{"kmh", "ms", "mph", "kn"}, c.WindSpeedUnit) {
+		return fmt.Errorf("invalid wind speed unit: %s", c.WindSpeedUnit)
+	}
+	if !slices.Contains([]string{"mm", "inch"}, c.PrecipitationUnit) {
+		return fmt.Errorf("invalid precipitation unit: %s", c.PrecipitationUnit)
+	}
+	if !slices.Contains([]string{"auto", "light", "dark"}, c.Theme) {
+		return fmt.Errorf("invalid theme: %s", c.Theme)
+	}
+	if c.RefreshInterval < 10*time.Second {
+		return fmt.Errorf("refresh interval too short: %v", c.RefreshInterval)
+	}
+	if c.CAPPollInterval < 10*time.Second {
+		return fmt.Errorf("CAP poll interval too short: %v", c.CAPPollInterval)
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("invalid timeout: %v", c.Timeout)
+	}
+	if c.ForecastDays < 1 || c.ForecastDays > 16 {
+		return fmt.Errorf("forecast days must be between 1 and 16: %d", c.ForecastDays)
+	}
+	for _, r := range c.Alerts {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setting is a configuration value which can be set from a flag or an environment variable.
+type setting struct {
+	name  string // name of the flag
+	usage string
+	set   func(c *Config, s string) error
+}
+
+var settings = []setting{
+	{"city", "show the weather for this city instead of the current location", setString(func(c *Config) *string { return &c.City })},
+	{"latitude", "latitude of a fixed location", setFloat(func(c *Config) *float64 { return &c.Latitude })},
+	{"longitude", "longitude of a fixed location", setFloat(func(c *Config) *float64 { return &c.Longitude })},
+	{"temperature-unit", "unit for temperatures: celsius or fahrenheit", setString(func(c *Config) *string { return &c.TemperatureUnit })},
+	{"wind-speed-unit", "unit for wind speeds: kmh, ms, mph or kn", setString(func(c *Config) *string { return &c.WindSpeedUnit })},
+	{"precipitation-unit", "unit for precipitation amounts: mm or inch", setString(func(c *Config) *string { return &c.PrecipitationUnit })},
+	{"refresh-interval", "interval between refreshes while the app is visible, e.g. 5m", setDuration(func(c *Config) *time.Duration { return &c.RefreshInterval })},
+	{"timeout", "timeout for API requests, e.g. 30s", setDuration(func(c *Config) *time.Duration { return &c.Timeout })},
+	{"forecast-days", "number of forecasted days (1-16)", setInt(func(c *Config) *int { return &c.ForecastDays })},
+	{"forecast-url", "base URL of the forecast API", setString(func(c *Config) *string { return &c.ForecastURL })},
+	{"archive-url", "base URL of the historical weather API", setString(func(c *Config) *string { return &c.ArchiveURL })},
+	{"geocoding-url", "base URL of the geocoding API", setString(func(c *Config) *string { return &c.GeocodingURL })},
+	{"location-url", "URL of the API for looking up the current location by IP", setString(func(c *Config) *string { return &c.LocationURL })},
+	{"proxy", "URL of a proxy for all API requests", setString(func(c *Config) *string { return &c.Proxy })},
+	{"cap-feed-url", "URL or file path of a CAP feed with official warnings", setString(func(c *Config) *string { return &c.CAPFeedURL })},
+	{"cap-poll-interval", "interval between fetches of the CAP feed, e.g. 5m", setDuration(func(c *Config) *time.Duration { return &c.CAPPollInterval })},
+	{"locations", "comma separated list of cities for the locations overview, e.g. Berlin,Paris", setList(func(c *Config) *[]string { return &c.Locations })},
+	{"models", "comma separated list of weather models to compare, e.g. ecmwf_ifs025,gfs_seamless", setList(func(c *Config) *[]string { return &c.Models })},
+	{"history-path", "path of the history database (default: weatherapp/history.db in the user's config directory)", setString(func(c *Config) *string { return &c.HistoryPath })},
+	{"icon-pack", "name of an icon pack in the icon packs directory (default: bundled icons)", setString(func(c *Config) *string { return &c.IconPack })},
+	{"icon-packs-dir", "directory with icon packs (default: weatherapp/icons in the user's config directory)", setString(func(c *Config) *string { return &c.IconPacksDir })},
+	{"theme", "theme of the app: auto, light or dark", setString(func(c *Config) *string { return &c.Theme })},
+}
+
+// boolSettings are settings which can be enabled with a bare flag, e.g. -history.
+var boolSettings = []setting{
+	{"history", "record forecasts for tracking their accuracy", setBool(func(c *Config) *bool { return &c.History })},
+	{"theme-tint", "tint the background by the current weather", setBool(func(c *Config) *bool { return &c.ThemeTint })},
+}
+
+func setString(field func(c *Config) *string) func(c *Config, s string) error {
+	return func(c *Config, s string) error {
+		*field(c) = s
+		return nil
+	}
+}
+
+func setList(field func(c *Config) *[]string) func(c *Config, s string) error {
+	return func(c *Config, s string) error {
+		var v []string
+		for _, x := range strings.Split(s, ",") {
+			if x := strings.TrimSpace(x); x != "" {
+				v = append(v, x)
+			}
+		}
+		*field(c) = v
+		return nil
+	}
+}
+
+func setBool(field func(c *Config) *bool) func(c *Config, s string) error {
+	return func(c *Config, s string) error {
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		*field(c) = v
+		return nil
+	}
+}
+
+func setFloat(field func(c *Config) *float64) func(c *Config, s string) error {
+	return func(c *Config, s string) error {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		*field(c) = v
+		return nil
+	}
+}
+
+func setInt(field func(c *Config) *int) func(c *Config, s string) error {
+	return func(c *Config, s string) error {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		*field(c) = v
+		return nil
+	}
+}
+
+func setDuration(field func(c *Config) *time.Duration) func(c *Config, s string) error {
+	return func(c *Config, s string) error {
+		v, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		*field(c) = v
+		return nil
+	}
+}
+
+// Load returns the configuration from command line arguments, environment and config file.
+// It also returns the remaining arguments after the flags.
+func Load(args []string) (Config, []string, error) {
+	flags := flag.NewFlagSet("weatherapp", flag.ContinueOnError)
+	configPath := flags.String("config", "", "path of the config file (default: weatherapp/config.toml in the user's config directory)")
+	flagValues := make(map[string]string)
+	for _, s := range settings {
+		flags.Func(s.name, s.usage, func(v string) error {
+			flagValues[s.name] = v
+			return nil
+		})
+	}
+	for _, s := range boolSettings {
+		flags.BoolFunc(s.name, s.usage, func(v string) error {
+			flagValues[s.name] = v
+			return nil
+		})
+	}
+	if err := flags.Parse(args); err != nil {
+		return Config{}, nil, err
+	}
+	c := Default()
+	path := *configPath
+	if path == "" {
+		path = os.Getenv(envPrefix + "CONFIG")
+	}
+	if err := c.loadFile(path); err != nil {
+		return Config{}, nil, err
+	}
+	err := c.apply(func(name string) (string, bool) {
+		return os.LookupEnv(envName(name))
+	}, func(name string) string {
+		return "environment variable " + envName(name)
+	})
+	if err != nil {
+		return Config{}, nil, err
+	}
+	err = c.apply(func(name string) (string, bool) {
+		v, ok := flagValues[name]
+		return v, ok
+	}, func(name string) string {
+		return "flag -" + name
+	})
+	if err != nil {
+		return Config{}, nil, err
+	}
+	if c.Alerts == nil {
+		c.Alerts = alerts.DefaultRules(c.TemperatureUnit, c.WindSpeedUnit)
+	}
+	if err := c.Validate(); err != nil {
+		return Config{}, nil, err
+	}
+	return c, flags.Args(), nil
+}
+
+// apply updates the configuration with the settings of a layer, e.g. the environment.
+// lookup returns the value of a setting in the layer and source describes a setting in error messages.
+//
+// The location is applied as a whole: A city or coordinates in the layer replace the location of lower layers.
+func (c *Config) apply(lookup func(name string) (string, bool), source func(name string) string) error {
+	hasCity, hasCoordinates, err := locationKeys(func(name string) bool {
+		_, ok := lookup(name)
+		return ok
+	})
+	if err != nil {
+		return fmt.Errorf("%s and %s: %w", source("latitude"), source("longitude"), err)
+	}
+	if hasCity || hasCoordinates {
+		c.clearLocation()
+	}
+	for _, s := range slices.Concat(settings, boolSettings) {
+		v, ok := lookup(s.name)
+		if !ok {
+			continue
+		}
+		if err := s.set(c, v); err != nil {
+			return fmt.Errorf("%s: %w", source(s.name), err)
+		}
+	}
+	c.hasCoordinates = c.hasCoordinates || hasCoordinates
+	return nil
+}
+
+func (c *Config) clearLocation() {
+	c.City = ""
+	c.Latitude = 0
+	c.Longitude = 0
+	c.hasCoordinates = false
+}
+
+// locationKeys reports whether a layer defines a city and coordinates.
+// It reports an error when only one of latitude and longitude is defined.
+func locationKeys(defined func(name string) bool) (hasCity bool, hasCoordinates bool, err error) {
+	hasLatitude, hasLongitude := defined("latitude"), defined("longitude")
+	if hasLatitude != hasLongitude {
+		return false, false, errors.New("must be set together")
+	}
+	return defined("city"), hasLatitude, nil
+}
+
+// loadFile updates the configuration from a TOML file.
+// When no path is given, the file at the default location is used if it exists.
+func (c *Config) loadFile(path string) error {
+	isDefault := path == ""
+	if isDefault {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return nil
+		}
+		path = filepath.Join(dir, "weatherapp", "config.toml")
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) && isDefault {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+	md, err := toml.NewDecoder(f).Decode(c)
+	if err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	if keys := md.Undecoded(); len(keys) > 0 {
+		return fmt.Errorf("config file %s: unknown key: %s", path, keys[0])
+	}
+	_, hasCoordinates, err := locationKeys(func(name string) bool {
+		return md.IsDefined(strings.ReplaceAll(name, "-", "_"))
+	})
+	if err != nil {
+		return fmt.Errorf("config file %s: latitude and longitude: %w", path, err)
+	}
+	c.hasCoordinates = hasCoordinates
+	return nil
+}
+
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// load returns the configuration and the remaining arguments for a config file, environment variables and flags.
+func load(t *testing.T, file string, env map[string]string, args ...string) (Config, []string, error) {
+	t.Helper()
+	for _, s := range slices.Concat(settings, boolSettings) {
+		t.Setenv(envName(s.name), "")
+		os.Unsetenv(envName(s.name))
+	}
+	t.Setenv(envPrefix+"CONFIG", "")
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return Load(append([]string{"-config", path}, args...))
+}
+
+func TestLoadLocation(t *testing.T) {
+	type location struct {
+		city           string
+		latitude       float64
+		longitude      float64
+		hasCoordinates bool
+	}
+	cases := []struct {
+		name string
+		file string
+		env  map[string]string
+		args []string
+		want location
+	}{
+		{"none", "", nil, nil, location{}},
+		{"city from file", `city = "Berlin"`, nil, nil, location{city: "Berlin"}},
+		{
+			"coordinates from file",
+			"latitude = 52.52\nlongitude = 13.41", nil, nil,
+			location{"", 52.52, 13.41, true},
+		},
+		{
+			"zero coordinates",
+			"latitude = 0.0\nlongitude = 0.0", nil, nil,
+			location{"", 0, 0, true},
+		},
+		{
+			"city and coordinates from same layer",
+			`city = "Home"` + "\nlatitude = 52.52\nlongitude = 13.41", nil, nil,
+			location{"Home", 52.52, 13.41, true},
+		},
+		{
+			"city from flag replaces coordinates from file",
+			"latitude = 52.52\nlongitude = 13.41", nil, []string{"-city", "Paris"},
+			location{city: "Paris"},
+		},
+		{
+			"coordinates from env replace city from file",
+			`city = "Berlin"`, map[string]string{"WEATHERAPP_LATITUDE": "48.85", "WEATHERAPP_LONGITUDE": "2.35"}, nil,
+			location{"", 48.85, 2.35, true},
+		},
+		{
+			"city from env replaces coordinates from file",
+			"latitude = 52.52\nlongitude = 13.41", map[string]string{"WEATHERAPP_CITY": "Paris"}, nil,
+			location{city: "Paris"},
+		},
+		{
+			"flags take precedence over env",
+			"", map[string]string{"WEATHERAPP_CITY": "Paris"}, []string{"-latitude", "0", "-longitude", "0"},
+			location{"", 0, 0, true},
+		},
+		{
+			"other layers keep location",
+			`city = "Berlin"`, map[string]string{"WEATHERAPP_THEME": "dark"}, []string{"-forecast-days", "3"},
+			location{city: "Berlin"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _, err := load(t, tc.file, tc.env, tc.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := location{c.City, c.Latitude, c.Longitude, c.HasCoordinates()}
+			if got != tc.want {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadIncompleteCoordinates(t *testing.T) {
+	cases := []struct {
+		name string
+		file string
+		env  map[string]string
+		args []string
+	}{
+		{"file", "latitude = 52.52", nil, nil},
+		{"env", "", map[string]string{"WEATHERAPP_LONGITUDE": "13.41"}, nil},
+		{"flag", "latitude = 52.52\nlongitude = 13.41", nil, []string{"-latitude", "48.85"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := load(t, tc.file, tc.env, tc.args...)
+			if err == nil || !strings.Contains(err.Error(), "must be set together") {
+				t.Errorf("got %v, want error", err)
+			}
+		})
+	}
+}
+
+func TestLoadBool(t *testing.T) {
+	cases := []struct {
+		name string
+		file string
+		env  map[string]string
+		args []string
+		want bool
+	}{
+		{"default", "", nil, nil, false},
+		{"bare flag", "", nil, []string{"-theme-tint"}, true},
+		{"flag with value", "theme_tint = true", nil, []string{"-theme-tint=false"}, false},
+		{"env", "", map[string]string{"WEATHERAPP_THEME_TINT": "true"}, nil, true},
+		{"file", "theme_tint = true", nil, nil, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _, err := load(t, tc.file, tc.env, tc.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.ThemeTint != tc.want {
+				t.Errorf("got %v, want %v", c.ThemeTint, tc.want)
+			}
+		})
+	}
+	t.Run("bare flag before command", func(t *testing.T) {
+		c, args, err := load(t, "history = false", nil, "-history", "serve")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !c.History {
+			t.Error("history not enabled")
+		}
+		if !slices.Equal(args, []string{"serve"}) {
+			t.Errorf("got args %v", args)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package forecast
+
+import (
+	"log"
+	"sync"
+)
+
+const (
+	// maxBatchSize is the maximum number of locations fetched in one request.
+	maxBatchSize = 100
+	// defaultConcurrency is the default number of parallel requests when falling back to single requests.
+	defaultConcurrency = 4
+)
+
+// Coordinates are the coordinates of a location.
+type Coordinates struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// BatchResult is the result for one location of a batch.
+type BatchResult struct {
+	Result Result
+	Err    error
+}
+
+// GetBatch returns the current weather and weather forecasts for many locations.
+// The results are in the same order as the locations.
+//
+// The locations are fetched with as few requests as possible.
+// When a batch request fails, the locations of that batch are fetched with single requests in parallel instead,
+// with at most concurrency requests at the same time. A concurrency < 1 means the default.
+func (c *Client) GetBatch(locations []Coordinates, concurrency int) []BatchResult {
+	if concurrency < 1 {
+		concurrency = defaultConcurrency
+	}
+	results := make([]BatchResult, len(locations))
+	for start := 0; start < len(locations); start += maxBatchSize {
+		end := min(start+maxBatchSize, len(locations))
+		if err := c.getBatch(locations[start:end], results[start:end]); err != nil {
+			log.Printf("WARNING: batch request for %d locations failed, falling back to single requests: %s", end-start, err)
+			c.getParallel(locations[start:end], results[start:end], concurrency)
+		}
+	}
+	return results
+}
+
+// getBatch fetches the forecasts for locations with a single request and stores them in results.
+func (c *Client) getBatch(locations []Coordinates, results []BatchResult) error {
+	lats := make([]float64, len(locations))
+	lons := make([]float64, len(locations))
+	for i, l := range locations {
+		lats[i] = l.Latitude
+		lons[i] = l.Longitude
+	}
+	responses, err := c.fetchData(lats, lons)
+	if err != nil {
+		return err
+	}
+	for i, r := range responses {
+		res, err := c.makeResult(r)
+		results[i] = BatchResult{Result: res, Err: err}
+	}
+	return nil
+}
+
+// getParallel fetches the forecasts for locations with one request each and stores them in results.
+func (c *Client) getParallel(locations []Coordinates, results []BatchResult, concurrency int) {
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, l := range locations {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			r, err := c.Get(l.Latitude, l.Longitude)
+			results[i] = BatchResult{Result: r, Err: err}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,144 @@
+package forecast
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ErikKalkoken/weatherapp/internal/apierror"
+)
+
+// batchHandler returns a handler which responds with one forecast per requested location.
+// The current temperature of each forecast is the latitude of its location.
+// respond can change the responses for batch requests and report a failed request with false.
+func batchHandler(t *testing.T, requests *[]int, respond func(lats []float64, responses []any) ([]any, bool)) http.HandlerFunc {
+	var mu sync.Mutex
+	return func(w http.ResponseWriter, r *http.Request) {
+		var lats []float64
+		for _, s := range strings.Split(r.URL.Query().Get("latitude"), ",") {
+			lat, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				t.Errorf("latitude %q: %s", s, err)
+			}
+			lats = append(lats, lat)
+		}
+		mu.Lock()
+		*requests = append(*requests, len(lats))
+		mu.Unlock()
+		var responses []any
+		for _, lat := range lats {
+			x := makeResponse(true)
+			x["latitude"] = lat
+			x["current"].(map[string]any)["temperature_2m"] = lat
+			responses = append(responses, x)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if len(lats) == 1 {
+			json.NewEncoder(w).Encode(responses[0])
+			return
+		}
+		responses, ok := respond(lats, responses)
+		if !ok {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(responses)
+	}
+}
+
+func makeCoordinates(n int) []Coordinates {
+	var locations []Coordinates
+	for i := range n {
+		locations = append(locations, Coordinates{Latitude: float64(i + 1), Longitude: 13.41})
+	}
+	return locations
+}
+
+func TestGetBatch(t *testing.T) {
+	cases := []struct {
+		name     string
+		count    int
+		respond  func(lats []float64, responses []any) ([]any, bool)
+		requests int // number of requests
+	}{
+		{
+			"one batch",
+			3,
+			func(_ []float64, responses []any) ([]any, bool) { return responses, true },
+			1,
+		},
+		{
+			"many batches",
+			maxBatchSize + 2,
+			func(_ []float64, responses []any) ([]any, bool) { return responses, true },
+			2,
+		},
+		{
+			"batch fails",
+			3,
+			func(_ []float64, _ []any) ([]any, bool) { return nil, false },
+			4,
+		},
+		{
+			"location count mismatch",
+			3,
+			func(_ []float64, responses []any) ([]any, bool) { return responses[:2], true },
+			4,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests []int
+			c := newTestServerClient(t, batchHandler(t, &requests, tc.respond))
+			locations := makeCoordinates(tc.count)
+			results := c.GetBatch(locations, 2)
+			if len(results) != len(locations) {
+				t.Fatalf("got %d results, want %d", len(results), len(locations))
+			}
+			for i, x := range results {
+				if x.Err != nil {
+					t.Errorf("%d: %s", i, x.Err)
+					continue
+				}
+				if got, want := x.Result.Current.Temperature2m, locations[i].Latitude; got != want {
+					t.Errorf("%d: got result for location %v, want %v", i, got, want)
+				}
+			}
+			if len(requests) != tc.requests {
+				t.Errorf("got %d requests, want %d", len(requests), tc.requests)
+			}
+		})
+	}
+}
+
+func TestGetBatchErrors(t *testing.T) {
+	t.Run("single requests fail", func(t *testing.T) {
+		c := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		})
+		results := c.GetBatch(makeCoordinates(3), 0)
+		if len(results) != 3 {
+			t.Fatalf("got %d results", len(results))
+		}
+		for i, x := range results {
+			if !errors.Is(x.Err, apierror.ErrHTTPStatus) {
+				t.Errorf("%d: got %v", i, x.Err)
+			}
+		}
+	})
+	t.Run("location count mismatch", func(t *testing.T) {
+		var requests []int
+		c := newTestServerClient(t, batchHandler(t, &requests, func(_ []float64, responses []any) ([]any, bool) {
+			return responses[:1], true
+		}))
+		_, err := c.fetchData([]float64{1, 2}, []float64{13.41, 13.41})
+		var e *apierror.MalformedValueError
+		if !errors.As(err, &e) || e.Field != "response" {
+			t.Errorf("got %#v", err)
+		}
+	})
+}
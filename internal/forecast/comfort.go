@@ -0,0 +1,24 @@
+package forecast
+
+import (
+	"github.com/ErikKalkoken/weatherapp/internal/comfort"
+	"github.com/ErikKalkoken/weatherapp/internal/wind"
+)
+
+// Comfort returns the comfort metrics for an hour.
+// All temperatures of the metrics are converted to the temperature unit of the result.
+// The humidex is dimensionless and always on the Celsius scale, so it is not converted.
+func (h ForecastHour) Comfort(units Units) comfort.Metrics {
+	fahrenheit := units.Temperature == "°F"
+	t := h.Temperature2m
+	if fahrenheit {
+		t = (t - 32) * 5 / 9
+	}
+	m := comfort.Compute(t, float64(h.RelativeHumidity2m), wind.ToKMH(h.WindSpeed10m, units.WindSpeed))
+	if fahrenheit {
+		for _, x := range []*float64{&m.DewPoint, &m.FeelsLike, &m.HeatIndex, &m.WBGT, &m.WindChill} {
+			*x = *x*9/5 + 32
+		}
+	}
+	return m
+}
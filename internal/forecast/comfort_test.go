@@ -0,0 +1,29 @@
+package forecast
+
+import (
+	"math"
+	"testing"
+)
+
+func TestComfort(t *testing.T) {
+	celsius := ForecastHour{Temperature2m: 30, RelativeHumidity2m: 74, WindSpeed10m: 10}
+	fahrenheit := ForecastHour{Temperature2m: 86, RelativeHumidity2m: 74, WindSpeed10m: 10}
+	c := celsius.Comfort(Units{Temperature: "°C", WindSpeed: "km/h"})
+	f := fahrenheit.Comfort(Units{Temperature: "°F", WindSpeed: "km/h"})
+	if math.Round(c.Humidex) != 42 {
+		t.Errorf("humidex: got %.1f, want 42", c.Humidex)
+	}
+	if math.Abs(f.Humidex-c.Humidex) > 0.01 {
+		t.Errorf("humidex in °F: got %.1f, want %.1f", f.Humidex, c.Humidex)
+	}
+	for name, x := range map[string][2]float64{
+		"dew point":  {c.DewPoint, f.DewPoint},
+		"feels like": {c.FeelsLike, f.FeelsLike},
+		"heat index": {c.HeatIndex, f.HeatIndex},
+		"WBGT":       {c.WBGT, f.WBGT},
+	} {
+		if want := x[0]*9/5 + 32; math.Abs(x[1]-want) > 0.01 {
+			t.Errorf("%s in °F: got %.1f, want %.1f", name, x[1], want)
+		}
+	}
+}
@@ -2,60 +2,186 @@
 package forecast
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/ErikKalkoken/weatherapp/internal/apierror"
 )
 
 // Weather forecast for an hour or current weather.
 type ForecastHour struct {
-	IsCurrent                bool
-	IsDay                    bool
-	PrecipitationProbability int
-	Temperature2m            float64
-	Time                     time.Time
-	WeatherCode              int
+	IsCurrent                bool      `json:"is_current"`
+	IsDay                    bool      `json:"is_day"`
+	Precipitation            float64   `json:"precipitation"` // sum of the preceding hour
+	PrecipitationProbability int       `json:"precipitation_probability"`
+	Rain                     float64   `json:"rain"` // sum of the preceding hour
+	RelativeHumidity2m       int       `json:"relative_humidity_2m"`
+	Showers                  float64   `json:"showers"`    // sum of the preceding hour
+	Snowfall                 float64   `json:"snowfall"`   // sum of the preceding hour
+	SnowDepth                float64   `json:"snow_depth"` // not available for the current weather
+	Temperature2m            float64   `json:"temperature_2m"`
+	Time                     time.Time `json:"time"`
+	WeatherCode              int       `json:"weather_code"`
+	WindDirection10m         float64   `json:"wind_direction_10m"` // direction the wind comes from in degrees
+	WindGusts10m             float64   `json:"wind_gusts_10m"`
+	WindSpeed10m             float64   `json:"wind_speed_10m"`
 }
 
 // Weather forecast for a day.
 type ForecastDay struct {
-	PrecipitationProbabilityMean int
-	Temperature2mMax             float64
-	Temperature2mMin             float64
-	Time                         time.Time
-	WeatherCode                  int
+	PrecipitationHours           float64   `json:"precipitation_hours"`
+	PrecipitationProbabilityMean int       `json:"precipitation_probability_mean"`
+	PrecipitationSum             float64   `json:"precipitation_sum"`
+	RainSum                      float64   `json:"rain_sum"`
+	ShowersSum                   float64   `json:"showers_sum"`
+	SnowfallSum                  float64   `json:"snowfall_sum"`
+	Temperature2mMax             float64   `json:"temperature_2m_max"`
+	Temperature2mMin             float64   `json:"temperature_2m_min"`
+	Time                         time.Time `json:"time"`
+	WeatherCode                  int       `json:"weather_code"`
+	WindGusts10mMax              float64   `json:"wind_gusts_10m_max"`
+}
+
+// NowcastStep is the precipitation forecast for 15 minutes.
+type NowcastStep struct {
+	Precipitation float64   `json:"precipitation"` // sum of the preceding 15 minutes
+	Time          time.Time `json:"time"`
+	WeatherCode   int       `json:"weather_code"` // of the hour containing the step
+}
+
+// Units are the units of the values in a result, e.g. "°C".
+type Units struct {
+	Temperature   string `json:"temperature"`
+	Precipitation string `json:"precipitation"`
+	Snowfall      string `json:"snowfall"`   // e.g. "cm"
+	SnowDepth     string `json:"snow_depth"` // e.g. "m"
+	WindSpeed     string `json:"wind_speed"`
+}
+
+// Result is the current weather together with the hourly and daily forecasts for a location.
+type Result struct {
+	Current ForecastHour   `json:"current"`
+	Hourly  []ForecastHour `json:"hourly"` // all coming hours of the forecasted days
+	Past    []ForecastHour `json:"past"`   // the last 24 hours up to the current hour
+	Nowcast []NowcastStep  `json:"nowcast"`
+	Daily   []ForecastDay  `json:"daily"`
+	Units   Units          `json:"units"`
+}
+
+// apiName is the name of the API in errors.
+const apiName = "Open-Meteo forecast API"
+
+// Client is a client for the Open-Meteo forecast API.
+type Client struct {
+	BaseURL           string
+	Days              int    // number of forecasted days
+	PrecipitationUnit string // mm or inch
+	TemperatureUnit   string // celsius or fahrenheit
+	WindSpeedUnit     string // kmh, ms, mph or kn
+
+	httpClient *http.Client
+}
+
+// NewClient returns a new client with default settings.
+func NewClient(httpClient *http.Client) *Client {
+	c := &Client{
+		BaseURL:           "https://api.open-meteo.com/v1/forecast",
+		Days:              10,
+		PrecipitationUnit: "mm",
+		TemperatureUnit:   "celsius",
+		WindSpeedUnit:     "kmh",
+		httpClient:        httpClient,
+	}
+	return c
 }
 
 // Get returns the current weather and weather forecasts for a location.
-func Get(httpClient *http.Client, lat float64, lon float64) (ForecastHour, []ForecastHour, []ForecastDay, error) {
-	response, err := fetchData(httpClient, lat, lon)
+func (c *Client) Get(lat float64, lon float64) (Result, error) {
+	responses, err := c.fetchData([]float64{lat}, []float64{lon})
 	if err != nil {
-		return ForecastHour{}, nil, nil, err
+		return Result{}, err
 	}
+	return c.makeResult(responses[0])
+}
+
+func (c *Client) makeResult(response forecastResponse) (Result, error) {
 	current, err := parseCurrent(response)
 	if err != nil {
-		return ForecastHour{}, nil, nil, err
+		return Result{}, err
 	}
 	vv, err := parseHourly(response)
 	if err != nil {
-		return ForecastHour{}, nil, nil, err
+		return Result{}, err
 	}
+	now := time.Now().UTC().Truncate(time.Hour)
 	hourly := make([]ForecastHour, 0)
+	past := make([]ForecastHour, 0)
 	for _, v := range vv {
-		if v.Time.After(time.Now().UTC().Truncate(time.Hour).Add(time.Hour)) {
+		if v.Time.After(now.Add(time.Hour)) {
 			hourly = append(hourly, v)
+		} else if !v.Time.Before(now.Add(-24*time.Hour)) && !v.Time.After(now) {
+			past = append(past, v)
 		}
-		if len(hourly) == 24 {
-			break
+	}
+	dd, err := parseDaily(response)
+	if err != nil {
+		return Result{}, err
+	}
+	daily := make([]ForecastDay, 0)
+	for _, v := range dd {
+		if !v.Time.Before(now.Truncate(24 * time.Hour)) {
+			daily = append(daily, v)
 		}
 	}
-	daily, err := parseDaily(response)
+	nowcast, err := parseNowcast(response)
 	if err != nil {
-		return ForecastHour{}, nil, nil, err
+		return Result{}, err
+	}
+	// Each hour covers the preceding hour.
+	for i, x := range nowcast {
+		j := slices.IndexFunc(vv, func(h ForecastHour) bool { return !h.Time.Before(x.Time) })
+		if j >= 0 {
+			nowcast[i].WeatherCode = vv[j].WeatherCode
+		}
+	}
+	units := Units{
+		Temperature:   response.CurrentUnits["temperature_2m"],
+		Precipitation: c.PrecipitationUnit,
+		Snowfall:      response.HourlyUnits["snowfall"],
+		SnowDepth:     response.HourlyUnits["snow_depth"],
+		WindSpeed:     response.CurrentUnits["wind_gusts_10m"],
+	}
+	return Result{Current: current, Hourly: hourly, Past: past, Nowcast: nowcast, Daily: daily, Units: units}, nil
+}
+
+// Yesterday returns the weather at this time yesterday and reports whether it was found.
+func (r Result) Yesterday() (ForecastHour, bool) {
+	t := r.Current.Time.Truncate(time.Hour).Add(-24 * time.Hour)
+	for _, v := range r.Past {
+		if v.Time.Equal(t) {
+			return v, true
+		}
+	}
+	return ForecastHour{}, false
+}
+
+// PrecipitationLast24h returns the sum of the precipitation of the last 24 hours.
+func (r Result) PrecipitationLast24h() float64 {
+	start := r.Current.Time.Truncate(time.Hour).Add(-24 * time.Hour)
+	var sum float64
+	for _, v := range r.Past {
+		if v.Time.After(start) {
+			sum += v.Precipitation
+		}
 	}
-	return current, hourly, daily, nil
+	return sum
 }
 
 type forecastResponse struct {
@@ -75,141 +201,348 @@ type forecastResponse struct {
 	DailyUnits   map[string]string `json:"daily_units"`
 	Hourly       map[string][]any  `json:"hourly"`
 	HourlyUnits  map[string]string `json:"hourly_units"`
+	Minutely15   map[string][]any  `json:"minutely_15"`
 }
 
-func fetchData(httpClient *http.Client, lat float64, lon float64) (forecastResponse, error) {
+// fetchData fetches the forecasts for one or more locations in one request.
+// It returns the responses in the same order as the locations.
+func (c *Client) fetchData(lats []float64, lons []float64) ([]forecastResponse, error) {
 	v := url.Values{}
-	v.Add("latitude", fmt.Sprint(lat))
-	v.Add("longitude", fmt.Sprint(lon))
+	v.Add("latitude", joinFloats(lats))
+	v.Add("longitude", joinFloats(lons))
 	v.Add("timezone", "GMT")
-	v.Add("forecast_days", "10")
-	v.Add("current", "temperature_2m,precipitation_probability,weather_code,is_day")
-	v.Add("daily", "temperature_2m_max,temperature_2m_min,precipitation_probability_mean,weather_code")
-	v.Add("hourly", "temperature_2m,precipitation_probability,weather_code,is_day")
-	u := "https://api.open-meteo.com/v1/forecast/?" + v.Encode()
-	resp, err := httpClient.Get(u)
+	v.Add("forecast_days", fmt.Sprint(c.Days))
+	v.Add("past_days", "1")
+	v.Add("past_hours", "24")
+	v.Add("temperature_unit", c.TemperatureUnit)
+	v.Add("wind_speed_unit", c.WindSpeedUnit)
+	v.Add("precipitation_unit", c.PrecipitationUnit)
+	v.Add("current", "temperature_2m,relative_humidity_2m,precipitation_probability,precipitation,rain,showers,snowfall,weather_code,is_day,wind_speed_10m,wind_direction_10m,wind_gusts_10m")
+	v.Add("daily", "temperature_2m_max,temperature_2m_min,precipitation_probability_mean,precipitation_sum,rain_sum,showers_sum,snowfall_sum,precipitation_hours,weather_code,wind_gusts_10m_max")
+	v.Add("minutely_15", "precipitation")
+	v.Add("forecast_minutely_15", fmt.Sprint(nowcastSteps+1)) // the first step may be in the past
+	v.Add("hourly", "temperature_2m,relative_humidity_2m,precipitation_probability,precipitation,rain,showers,snowfall,snow_depth,weather_code,is_day,wind_speed_10m,wind_direction_10m,wind_gusts_10m")
+	u := c.BaseURL + "?" + v.Encode()
+	resp, err := c.httpClient.Get(u)
 	if err != nil {
-		return forecastResponse{}, fmt.Errorf("making request to open meteo API: %w", err)
+		return nil, &apierror.NetworkError{API: apiName, Err: err}
 	}
 	defer resp.Body.Close()
 
-	var response forecastResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return response, fmt.Errorf("decoding response: %w", err)
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &apierror.NetworkError{API: apiName, Err: err}
+	}
+	// The response is a list for multiple locations and a single object otherwise, e.g. for errors.
+	var responses []forecastResponse
+	if b := bytes.TrimSpace(data); len(b) > 0 && b[0] == '[' {
+		err = json.Unmarshal(b, &responses)
+	} else {
+		var response forecastResponse
+		err = json.Unmarshal(b, &response)
+		responses = []forecastResponse{response}
+	}
+	if err != nil {
+		if resp.StatusCode >= 400 {
+			return nil, &apierror.HTTPStatusError{API: apiName, StatusCode: resp.StatusCode, Status: resp.Status}
+		}
+		return nil, &apierror.MalformedValueError{Field: "response", Err: err}
+	}
+	for _, r := range responses {
+		if r.Error {
+			return nil, &apierror.APIError{API: apiName, Reason: r.Reason}
+		}
 	}
-	if response.Error {
-		return forecastResponse{}, fmt.Errorf("Error from open meteo: %s", response.Reason)
+	if resp.StatusCode >= 400 {
+		return nil, &apierror.HTTPStatusError{API: apiName, StatusCode: resp.StatusCode, Status: resp.Status}
 	}
+	if len(responses) != len(lats) {
+		return nil, &apierror.MalformedValueError{
+			Field: "response",
+			Err:   fmt.Errorf("expected %d locations, got %d", len(lats), len(responses)),
+		}
+	}
+	return responses, nil
+}
 
-	return response, nil
+func joinFloats(vv []float64) string {
+	s := make([]string, len(vv))
+	for i, v := range vv {
+		s[i] = fmt.Sprint(v)
+	}
+	return strings.Join(s, ",")
 }
 
 func parseCurrent(response forecastResponse) (ForecastHour, error) {
 	c := ForecastHour{IsCurrent: true}
-	x, err := time.Parse("2006-01-02T15:04", response.Current["time"].(string))
+	values := response.Current
+	if values == nil {
+		return c, &apierror.MissingFieldError{Field: "current"}
+	}
+	t, err := timeValue("current.time", values["time"], "2006-01-02T15:04")
 	if err != nil {
 		return c, err
 	}
-	c.Time = x.UTC()
-	v1, ok := response.Current["temperature_2m"]
-	if !ok {
-		return c, fmt.Errorf("missing data")
+	c.Time = t
+	floats := []struct {
+		name  string
+		field *float64
+	}{
+		{"temperature_2m", &c.Temperature2m},
+		{"precipitation", &c.Precipitation},
+		{"rain", &c.Rain},
+		{"showers", &c.Showers},
+		{"snowfall", &c.Snowfall},
+		{"wind_speed_10m", &c.WindSpeed10m},
+		{"wind_direction_10m", &c.WindDirection10m},
+		{"wind_gusts_10m", &c.WindGusts10m},
 	}
-	c.Temperature2m = v1.(float64)
-	v2, ok := response.Current["precipitation_probability"]
-	if !ok {
-		return c, fmt.Errorf("missing data")
+	for _, x := range floats {
+		v, ok := values[x.name]
+		if !ok {
+			return c, &apierror.MissingFieldError{Field: "current." + x.name}
+		}
+		*x.field, err = floatValue("current."+x.name, v)
+		if err != nil {
+			return c, err
+		}
 	}
-	c.PrecipitationProbability = int(v2.(float64))
-	v3, ok := response.Current["weather_code"]
-	if !ok {
-		return c, fmt.Errorf("missing data")
+	ints := []struct {
+		name     string
+		field    *int
+		nullable bool
+	}{
+		// Precipitation probabilities are not available for all models.
+		{"precipitation_probability", &c.PrecipitationProbability, true},
+		{"relative_humidity_2m", &c.RelativeHumidity2m, false},
+		{"weather_code", &c.WeatherCode, false},
 	}
-	c.WeatherCode = int(v3.(float64))
-	v4, ok := response.Current["is_day"]
+	for _, x := range ints {
+		v, ok := values[x.name]
+		if !ok {
+			return c, &apierror.MissingFieldError{Field: "current." + x.name}
+		}
+		if v == nil && x.nullable {
+			continue
+		}
+		f, err := floatValue("current."+x.name, v)
+		if err != nil {
+			return c, err
+		}
+		*x.field = int(f)
+	}
+	v, ok := values["is_day"]
 	if !ok {
-		return c, fmt.Errorf("missing data")
+		return c, &apierror.MissingFieldError{Field: "current.is_day"}
 	}
-	c.IsDay = v4.(float64) == 1.0
+	isDay, err := floatValue("current.is_day", v)
+	if err != nil {
+		return c, err
+	}
+	c.IsDay = isDay == 1.0
 	return c, nil
 }
 
 func parseHourly(response forecastResponse) ([]ForecastHour, error) {
-	hourly := make([]ForecastHour, len(response.Hourly["time"]))
-	for i, v := range response.Hourly["time"] {
-		x, err := time.Parse("2006-01-02T15:04", v.(string))
+	times, err := timeSeries(response.Hourly, "hourly", "2006-01-02T15:04")
+	if err != nil {
+		return nil, err
+	}
+	hourly := make([]ForecastHour, len(times))
+	for i, t := range times {
+		hourly[i].Time = t
+	}
+	floats := []struct {
+		name     string
+		field    func(h *ForecastHour) *float64
+		nullable bool
+	}{
+		{"temperature_2m", func(h *ForecastHour) *float64 { return &h.Temperature2m }, false},
+		{"precipitation", func(h *ForecastHour) *float64 { return &h.Precipitation }, false},
+		{"rain", func(h *ForecastHour) *float64 { return &h.Rain }, false},
+		{"showers", func(h *ForecastHour) *float64 { return &h.Showers }, false},
+		{"snowfall", func(h *ForecastHour) *float64 { return &h.Snowfall }, false},
+		// Snow depth is not available for all hours of all models.
+		{"snow_depth", func(h *ForecastHour) *float64 { return &h.SnowDepth }, true},
+		{"wind_speed_10m", func(h *ForecastHour) *float64 { return &h.WindSpeed10m }, false},
+		{"wind_direction_10m", func(h *ForecastHour) *float64 { return &h.WindDirection10m }, false},
+		{"wind_gusts_10m", func(h *ForecastHour) *float64 { return &h.WindGusts10m }, false},
+	}
+	for _, x := range floats {
+		vv, err := floatSeries(response.Hourly, "hourly", x.name, len(times), x.nullable)
 		if err != nil {
 			return nil, err
 		}
-		hourly[i].Time = x.UTC()
+		for i, v := range vv {
+			*x.field(&hourly[i]) = v
+		}
 	}
-	vv1, ok := response.Hourly["temperature_2m"]
-	if !ok {
-		return nil, fmt.Errorf("missing data")
+	ints := []struct {
+		name     string
+		field    func(h *ForecastHour) *int
+		nullable bool
+	}{
+		// Precipitation probabilities are not available for all hours of all models.
+		{"precipitation_probability", func(h *ForecastHour) *int { return &h.PrecipitationProbability }, true},
+		{"relative_humidity_2m", func(h *ForecastHour) *int { return &h.RelativeHumidity2m }, false},
+		{"weather_code", func(h *ForecastHour) *int { return &h.WeatherCode }, false},
 	}
-	for i, v := range vv1 {
-		hourly[i].Temperature2m = v.(float64)
+	for _, x := range ints {
+		vv, err := floatSeries(response.Hourly, "hourly", x.name, len(times), x.nullable)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range vv {
+			*x.field(&hourly[i]) = int(v)
+		}
 	}
-	vv2, ok := response.Hourly["precipitation_probability"]
-	if !ok {
-		return nil, fmt.Errorf("missing data")
+	vv, err := floatSeries(response.Hourly, "hourly", "is_day", len(times), false)
+	if err != nil {
+		return nil, err
 	}
-	for i, v := range vv2 {
-		hourly[i].PrecipitationProbability = int(v.(float64))
+	for i, v := range vv {
+		hourly[i].IsDay = v == 1.0
 	}
-	vv3, ok := response.Hourly["weather_code"]
-	if !ok {
-		return nil, fmt.Errorf("missing data")
+	return hourly, nil
+}
+
+// parseNowcast returns the coming steps of the nowcast.
+// The nowcast is empty when the response has no 15-minutely data.
+func parseNowcast(response forecastResponse) ([]NowcastStep, error) {
+	if len(response.Minutely15) == 0 {
+		return []NowcastStep{}, nil
 	}
-	for i, v := range vv3 {
-		hourly[i].WeatherCode = int(v.(float64))
+	times, err := timeSeries(response.Minutely15, "minutely_15", "2006-01-02T15:04")
+	if err != nil {
+		return nil, err
 	}
-	vv4, ok := response.Hourly["is_day"]
-	if !ok {
-		return nil, fmt.Errorf("missing data")
+	vv, err := floatSeries(response.Minutely15, "minutely_15", "precipitation", len(times), true)
+	if err != nil {
+		return nil, err
 	}
-	for i, v := range vv4 {
-		hourly[i].IsDay = v.(float64) == 1.0
+	start := time.Now().UTC().Truncate(15 * time.Minute)
+	nowcast := make([]NowcastStep, 0, nowcastSteps)
+	for i, t := range times {
+		if !t.After(start) || response.Minutely15["precipitation"][i] == nil {
+			continue
+		}
+		if len(nowcast) == nowcastSteps {
+			break
+		}
+		nowcast = append(nowcast, NowcastStep{Precipitation: vv[i], Time: t})
 	}
-	return hourly, nil
+	return nowcast, nil
 }
 
 func parseDaily(response forecastResponse) ([]ForecastDay, error) {
-	daily := make([]ForecastDay, len(response.Daily["time"]))
-	for i, v := range response.Daily["time"] {
-		x, err := time.Parse("2006-01-02", v.(string))
+	times, err := timeSeries(response.Daily, "daily", "2006-01-02")
+	if err != nil {
+		return nil, err
+	}
+	daily := make([]ForecastDay, len(times))
+	for i, t := range times {
+		daily[i].Time = t
+	}
+	floats := []struct {
+		name  string
+		field func(d *ForecastDay) *float64
+	}{
+		{"temperature_2m_min", func(d *ForecastDay) *float64 { return &d.Temperature2mMin }},
+		{"temperature_2m_max", func(d *ForecastDay) *float64 { return &d.Temperature2mMax }},
+		{"wind_gusts_10m_max", func(d *ForecastDay) *float64 { return &d.WindGusts10mMax }},
+		{"precipitation_sum", func(d *ForecastDay) *float64 { return &d.PrecipitationSum }},
+		{"rain_sum", func(d *ForecastDay) *float64 { return &d.RainSum }},
+		{"showers_sum", func(d *ForecastDay) *float64 { return &d.ShowersSum }},
+		{"snowfall_sum", func(d *ForecastDay) *float64 { return &d.SnowfallSum }},
+		{"precipitation_hours", func(d *ForecastDay) *float64 { return &d.PrecipitationHours }},
+	}
+	for _, x := range floats {
+		vv, err := floatSeries(response.Daily, "daily", x.name, len(times), false)
 		if err != nil {
 			return nil, err
 		}
-		daily[i].Time = x.UTC()
+		for i, v := range vv {
+			*x.field(&daily[i]) = v
+		}
 	}
-	vv1, ok := response.Daily["temperature_2m_min"]
-	if !ok {
-		return nil, fmt.Errorf("missing data")
+	ints := []struct {
+		name     string
+		field    func(d *ForecastDay) *int
+		nullable bool
+	}{
+		{"precipitation_probability_mean", func(d *ForecastDay) *int { return &d.PrecipitationProbabilityMean }, true},
+		{"weather_code", func(d *ForecastDay) *int { return &d.WeatherCode }, false},
 	}
-	for i, v := range vv1 {
-		daily[i].Temperature2mMin = v.(float64)
+	for _, x := range ints {
+		vv, err := floatSeries(response.Daily, "daily", x.name, len(times), x.nullable)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range vv {
+			*x.field(&daily[i]) = int(v)
+		}
 	}
-	vv2, ok := response.Daily["temperature_2m_max"]
+	return daily, nil
+}
+
+// timeSeries returns the times of a series, e.g. of the hourly forecast.
+func timeSeries(values map[string][]any, section string, layout string) ([]time.Time, error) {
+	vv, ok := values["time"]
 	if !ok {
-		return nil, fmt.Errorf("missing data")
+		return nil, &apierror.MissingFieldError{Field: section + ".time"}
 	}
-	for i, v := range vv2 {
-		daily[i].Temperature2mMax = v.(float64)
+	times := make([]time.Time, len(vv))
+	for i, v := range vv {
+		t, err := timeValue(section+".time", v, layout)
+		if err != nil {
+			return nil, err
+		}
+		times[i] = t
 	}
-	vv3, ok := response.Daily["precipitation_probability_mean"]
+	return times, nil
+}
+
+// floatSeries returns the values of a field of a series with n values.
+// When nullable is true null values are returned as 0.
+func floatSeries(values map[string][]any, section, name string, n int, nullable bool) ([]float64, error) {
+	field := section + "." + name
+	vv, ok := values[name]
 	if !ok {
-		return nil, fmt.Errorf("missing data")
+		return nil, &apierror.MissingFieldError{Field: field}
 	}
-	for i, v := range vv3 {
-		daily[i].PrecipitationProbabilityMean = int(v.(float64))
+	if len(vv) != n {
+		return nil, &apierror.MalformedValueError{Field: field, Err: fmt.Errorf("expected %d values, got %d", n, len(vv))}
 	}
-	vv4, ok := response.Daily["weather_code"]
+	result := make([]float64, n)
+	for i, v := range vv {
+		if v == nil && nullable {
+			continue
+		}
+		x, err := floatValue(field, v)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = x
+	}
+	return result, nil
+}
+
+func floatValue(field string, v any) (float64, error) {
+	x, ok := v.(float64)
 	if !ok {
-		return nil, fmt.Errorf("missing data")
+		return 0, &apierror.MalformedValueError{Field: field, Value: v}
 	}
-	for i, v := range vv4 {
-		daily[i].WeatherCode = int(v.(float64))
+	return x, nil
+}
+
+func timeValue(field string, v any, layout string) (time.Time, error) {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, &apierror.MalformedValueError{Field: field, Value: v}
 	}
-	return daily, nil
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return time.Time{}, &apierror.MalformedValueError{Field: field, Value: v, Err: err}
+	}
+	return t.UTC(), nil
 }
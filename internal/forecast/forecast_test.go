@@ -0,0 +1,185 @@
+package forecast
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ErikKalkoken/weatherapp/internal/apierror"
+)
+
+// makeResponse returns a forecast response with 48 hours from yesterday and 3 days from today.
+// Precipitation probabilities are null for the first hours and the first day, as returned for some models.
+func makeResponse(withNowcast bool) map[string]any {
+	now := time.Now().UTC().Truncate(time.Hour)
+	hourly := map[string][]any{}
+	for i := range 48 {
+		t := now.Add(time.Duration(i-24) * time.Hour)
+		hourly["time"] = append(hourly["time"], t.Format("2006-01-02T15:04"))
+		var probability any
+		if i >= 30 {
+			probability = 40.0
+		}
+		hourly["precipitation_probability"] = append(hourly["precipitation_probability"], probability)
+		for _, name := range []string{
+			"temperature_2m", "relative_humidity_2m", "precipitation", "rain", "showers", "snowfall",
+			"snow_depth", "weather_code", "is_day", "wind_speed_10m", "wind_direction_10m", "wind_gusts_10m",
+		} {
+			hourly[name] = append(hourly[name], 1.0)
+		}
+	}
+	daily := map[string][]any{}
+	for i := range 3 {
+		daily["time"] = append(daily["time"], now.AddDate(0, 0, i).Format("2006-01-02"))
+		var probability any
+		if i > 0 {
+			probability = 20.0
+		}
+		daily["precipitation_probability_mean"] = append(daily["precipitation_probability_mean"], probability)
+		for _, name := range []string{
+			"temperature_2m_max", "temperature_2m_min", "precipitation_sum", "rain_sum", "showers_sum",
+			"snowfall_sum", "precipitation_hours", "weather_code", "wind_gusts_10m_max",
+		} {
+			daily[name] = append(daily[name], 1.0)
+		}
+	}
+	current := map[string]any{"time": now.Format("2006-01-02T15:04"), "precipitation_probability": nil}
+	for _, name := range []string{
+		"temperature_2m", "relative_humidity_2m", "precipitation", "rain", "showers", "snowfall",
+		"weather_code", "is_day", "wind_speed_10m", "wind_direction_10m", "wind_gusts_10m",
+	} {
+		current[name] = 1.0
+	}
+	r := map[string]any{
+		"latitude":      52.52,
+		"longitude":     13.41,
+		"current":       current,
+		"current_units": map[string]string{"temperature_2m": "°C", "wind_gusts_10m": "km/h"},
+		"hourly":        hourly,
+		"hourly_units":  map[string]string{"snowfall": "cm", "snow_depth": "m"},
+		"daily":         daily,
+	}
+	if withNowcast {
+		minutely := map[string][]any{}
+		start := time.Now().UTC().Truncate(15 * time.Minute)
+		for i := range nowcastSteps + 1 {
+			t := start.Add(time.Duration(i) * 15 * time.Minute) // the first step is not in the future
+			minutely["time"] = append(minutely["time"], t.Format("2006-01-02T15:04"))
+			minutely["precipitation"] = append(minutely["precipitation"], 0.5)
+		}
+		r["minutely_15"] = minutely
+	}
+	return r
+}
+
+func newTestClient(t *testing.T, response map[string]any) *Client {
+	return newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	})
+}
+
+func TestGet(t *testing.T) {
+	r, err := newTestClient(t, makeResponse(true)).Get(52.52, 13.41)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Current.PrecipitationProbability != 0 || r.Current.Temperature2m != 1 {
+		t.Errorf("current: got %+v", r.Current)
+	}
+	if len(r.Hourly) == 0 || len(r.Past) == 0 {
+		t.Fatalf("got %d hours and %d past hours", len(r.Hourly), len(r.Past))
+	}
+	if got := r.Hourly[len(r.Hourly)-1].PrecipitationProbability; got != 40 {
+		t.Errorf("last hour: got probability %d, want 40", got)
+	}
+	if got := r.Past[0].PrecipitationProbability; got != 0 {
+		t.Errorf("first past hour: got probability %d, want 0", got)
+	}
+	if len(r.Daily) != 3 || r.Daily[0].PrecipitationProbabilityMean != 0 || r.Daily[1].PrecipitationProbabilityMean != 20 {
+		t.Errorf("daily: got %+v", r.Daily)
+	}
+	if len(r.Nowcast) != nowcastSteps {
+		t.Errorf("got %d nowcast steps, want %d", len(r.Nowcast), nowcastSteps)
+	}
+	if r.Units.Temperature != "°C" || r.Units.WindSpeed != "km/h" {
+		t.Errorf("units: got %+v", r.Units)
+	}
+}
+
+func TestGetWithoutNowcast(t *testing.T) {
+	r, err := newTestClient(t, makeResponse(false)).Get(52.52, 13.41)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Nowcast == nil || len(r.Nowcast) != 0 {
+		t.Errorf("got nowcast %v, want empty", r.Nowcast)
+	}
+}
+
+func TestGetErrors(t *testing.T) {
+	t.Run("missing field", func(t *testing.T) {
+		response := makeResponse(true)
+		delete(response["hourly"].(map[string][]any), "weather_code")
+		_, err := newTestClient(t, response).Get(52.52, 13.41)
+		if !errors.Is(err, apierror.ErrMissingField) {
+			t.Fatalf("got %v, want missing field", err)
+		}
+		var e *apierror.MissingFieldError
+		if !errors.As(err, &e) || e.Field != "hourly.weather_code" {
+			t.Errorf("got %#v", err)
+		}
+	})
+	t.Run("malformed value", func(t *testing.T) {
+		response := makeResponse(true)
+		response["hourly"].(map[string][]any)["temperature_2m"][0] = "warm"
+		_, err := newTestClient(t, response).Get(52.52, 13.41)
+		var e *apierror.MalformedValueError
+		if !errors.Is(err, apierror.ErrMalformedValue) || !errors.As(err, &e) || e.Field != "hourly.temperature_2m" {
+			t.Errorf("got %#v", err)
+		}
+	})
+	t.Run("HTTP status", func(t *testing.T) {
+		c := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		})
+		_, err := c.Get(52.52, 13.41)
+		var e *apierror.HTTPStatusError
+		if !errors.Is(err, apierror.ErrHTTPStatus) || !errors.As(err, &e) || e.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("got %#v", err)
+		}
+	})
+	t.Run("API error body", func(t *testing.T) {
+		c := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": true, "reason": "Latitude must be in range of -90 to 90°."}`))
+		})
+		_, err := c.Get(152.52, 13.41)
+		var e *apierror.APIError
+		if !errors.Is(err, apierror.ErrAPI) || !errors.As(err, &e) || e.Reason != "Latitude must be in range of -90 to 90°." {
+			t.Errorf("got %#v", err)
+		}
+	})
+	t.Run("network", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		srv.Close()
+		c := NewClient(http.DefaultClient)
+		c.BaseURL = srv.URL
+		_, err := c.Get(52.52, 13.41)
+		var e *apierror.NetworkError
+		if !errors.Is(err, apierror.ErrNetwork) || !errors.As(err, &e) || e.API != apiName {
+			t.Errorf("got %#v", err)
+		}
+	})
+}
+
+func newTestServerClient(t *testing.T, handler http.HandlerFunc) *Client {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	c := NewClient(srv.Client())
+	c.BaseURL = srv.URL
+	return c
+}
@@ -0,0 +1,232 @@
+package forecast
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ErikKalkoken/weatherapp/internal/apierror"
+)
+
+// Confidence is how much weather models agree on a forecast.
+type Confidence uint
+
+const (
+	ConfidenceLow Confidence = iota
+	ConfidenceMedium
+	ConfidenceHigh
+)
+
+func (c Confidence) String() string {
+	switch c {
+	case ConfidenceHigh:
+		return "high"
+	case ConfidenceMedium:
+		return "medium"
+	}
+	return "low"
+}
+
+func (c Confidence) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// ModelHour is the forecast of a weather model for an hour.
+type ModelHour struct {
+	Precipitation float64   `json:"precipitation"`
+	Temperature2m float64   `json:"temperature_2m"`
+	Time          time.Time `json:"time"`
+}
+
+// ModelForecast is the hourly forecast of a weather model.
+type ModelForecast struct {
+	Model  string      `json:"model"`
+	Hourly []ModelHour `json:"hourly"` // ends early when the model has a shorter range
+}
+
+// DaySpread describes how much the weather models differ in their forecast for a day.
+type DaySpread struct {
+	Confidence        Confidence `json:"confidence"`
+	Models            int        `json:"models"`              // number of models with a forecast for this day
+	PrecipitationMax  float64    `json:"precipitation_max"`   // highest daily precipitation sum of the models
+	PrecipitationMin  float64    `json:"precipitation_min"`   // lowest daily precipitation sum of the models
+	Temperature2mMax  float64    `json:"temperature_2m_max"`  // highest daily maximum of the models
+	Temperature2mMin  float64    `json:"temperature_2m_min"`  // lowest daily maximum of the models
+	TemperatureStdDev float64    `json:"temperature_std_dev"` // standard deviation of the daily maximums of the models
+	Time              time.Time  `json:"time"`
+}
+
+// Comparison is the forecast of several weather models for the same location.
+type Comparison struct {
+	Models []ModelForecast `json:"models"`
+	Days   []DaySpread     `json:"days"`
+	Units  Units           `json:"units"`
+}
+
+// Compare returns the hourly forecasts of several weather models for a location, e.g. "ecmwf_ifs025" or "gfs_seamless",
+// and how much they differ for each day.
+func (c *Client) Compare(lat float64, lon float64, models []string) (Comparison, error) {
+	if len(models) == 0 {
+		return Comparison{}, fmt.Errorf("no models")
+	}
+	v := url.Values{}
+	v.Add("latitude", fmt.Sprint(lat))
+	v.Add("longitude", fmt.Sprint(lon))
+	v.Add("timezone", "GMT")
+	v.Add("forecast_days", fmt.Sprint(c.Days))
+	v.Add("temperature_unit", c.TemperatureUnit)
+	v.Add("wind_speed_unit", c.WindSpeedUnit)
+	v.Add("precipitation_unit", c.PrecipitationUnit)
+	v.Add("hourly", "temperature_2m,precipitation")
+	v.Add("models", strings.Join(models, ","))
+	resp, err := c.httpClient.Get(c.BaseURL + "?" + v.Encode())
+	if err != nil {
+		return Comparison{}, &apierror.NetworkError{API: apiName, Err: err}
+	}
+	defer resp.Body.Close()
+
+	// Values are suffixed with the model, e.g. "temperature_2m_gfs_seamless".
+	// Values are null for hours beyond the range of a model.
+	var response struct {
+		Error       bool              `json:"error"`
+		Reason      string            `json:"reason"`
+		Hourly      map[string][]any  `json:"hourly"`
+		HourlyUnits map[string]string `json:"hourly_units"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		if resp.StatusCode >= 400 {
+			return Comparison{}, &apierror.HTTPStatusError{API: apiName, StatusCode: resp.StatusCode, Status: resp.Status}
+		}
+		return Comparison{}, &apierror.MalformedValueError{Field: "response", Err: err}
+	}
+	if response.Error {
+		return Comparison{}, &apierror.APIError{API: apiName, Reason: response.Reason}
+	}
+	times, err := timeSeries(response.Hourly, "hourly", "2006-01-02T15:04")
+	if err != nil {
+		return Comparison{}, err
+	}
+	result := Comparison{
+		Units: Units{
+			Temperature:   response.HourlyUnits["temperature_2m_"+models[0]],
+			Precipitation: c.PrecipitationUnit,
+			WindSpeed:     c.WindSpeedUnit,
+		},
+	}
+	now := time.Now().UTC().Truncate(time.Hour)
+	for _, m := range models {
+		temperatures, err := floatSeries(response.Hourly, "hourly", "temperature_2m_"+m, len(times), true)
+		if err != nil {
+			return Comparison{}, err
+		}
+		precipitation, err := floatSeries(response.Hourly, "hourly", "precipitation_"+m, len(times), true)
+		if err != nil {
+			return Comparison{}, err
+		}
+		f := ModelForecast{Model: m, Hourly: make([]ModelHour, 0)}
+		for i, t := range times {
+			if response.Hourly["temperature_2m_"+m][i] == nil || response.Hourly["precipitation_"+m][i] == nil {
+				break
+			}
+			if t.Before(now) {
+				continue
+			}
+			f.Hourly = append(f.Hourly, ModelHour{
+				Precipitation: precipitation[i],
+				Temperature2m: temperatures[i],
+				Time:          t,
+			})
+		}
+		result.Models = append(result.Models, f)
+	}
+	result.Days = spreads(result.Models, result.Units)
+	return result, nil
+}
+
+// spreads returns the spread of the models for each day.
+func spreads(models []ModelForecast, units Units) []DaySpread {
+	type daily struct {
+		max, precipitation float64
+		hours              int
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	byDay := make(map[time.Time][]daily)
+	for _, m := range models {
+		days := make(map[time.Time]*daily)
+		for _, h := range m.Hourly {
+			day := h.Time.Truncate(24 * time.Hour)
+			d, ok := days[day]
+			if !ok {
+				d = &daily{max: h.Temperature2m}
+				days[day] = d
+			}
+			d.max = max(d.max, h.Temperature2m)
+			d.precipitation += h.Precipitation
+			d.hours++
+		}
+		for day, d := range days {
+			// Skip the last day of a model when it is cut off by the end of its range.
+			if d.hours < 24 && !day.Equal(today) {
+				continue
+			}
+			byDay[day] = append(byDay[day], *d)
+		}
+	}
+	// Thresholds for the spread of the daily maximum temperatures in °C
+	// and for a dry and a wet day in mm.
+	high, medium := 1.0, 2.5
+	if units.Temperature == "°F" {
+		high, medium = high*1.8, medium*1.8
+	}
+	dry, wet := 0.1, 1.0
+	if units.Precipitation == "inch" {
+		dry, wet = dry/25.4, wet/25.4
+	}
+	spreads := make([]DaySpread, 0)
+	for day, dd := range byDay {
+		if len(dd) < 2 {
+			continue
+		}
+		x := DaySpread{
+			Models:           len(dd),
+			PrecipitationMax: dd[0].precipitation,
+			PrecipitationMin: dd[0].precipitation,
+			Temperature2mMax: dd[0].max,
+			Temperature2mMin: dd[0].max,
+			Time:             day,
+		}
+		var sum float64
+		for _, d := range dd {
+			x.PrecipitationMax = max(x.PrecipitationMax, d.precipitation)
+			x.PrecipitationMin = min(x.PrecipitationMin, d.precipitation)
+			x.Temperature2mMax = max(x.Temperature2mMax, d.max)
+			x.Temperature2mMin = min(x.Temperature2mMin, d.max)
+			sum += d.max
+		}
+		mean := sum / float64(len(dd))
+		var variance float64
+		for _, d := range dd {
+			variance += (d.max - mean) * (d.max - mean)
+		}
+		x.TemperatureStdDev = math.Sqrt(variance / float64(len(dd)))
+		// Models disagreeing on whether it will rain at all lowers the confidence too.
+		rainDisagrees := x.PrecipitationMin < dry && x.PrecipitationMax >= wet
+		switch {
+		case x.TemperatureStdDev <= high && !rainDisagrees:
+			x.Confidence = ConfidenceHigh
+		case x.TemperatureStdDev <= medium:
+			x.Confidence = ConfidenceMedium
+		default:
+			x.Confidence = ConfidenceLow
+		}
+		spreads = append(spreads, x)
+	}
+	slices.SortFunc(spreads, func(a, b DaySpread) int {
+		return a.Time.Compare(b.Time)
+	})
+	return spreads
+}
@@ -0,0 +1,103 @@
+package forecast
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ErikKalkoken/weatherapp/internal/weathercode"
+)
+
+// nowcastSteps is the number of 15 minute steps in a nowcast, i.e. the next 2 hours.
+const nowcastSteps = 8
+
+// IsWet reports whether there is noticeable precipitation in this step.
+func (x NowcastStep) IsWet(unit string) bool {
+	threshold := 0.1 // mm
+	if unit == "inch" {
+		threshold /= 25.4
+	}
+	return x.Precipitation >= threshold
+}
+
+// NowcastText returns a short description of the precipitation in the coming two hours,
+// e.g. "Rain starting in 20 min, ending in 55 min".
+// The kind of precipitation is taken from the weather codes of the wet steps.
+func (r Result) NowcastText(now time.Time) string {
+	if len(r.Nowcast) == 0 {
+		return ""
+	}
+	start, end := -1, -1
+	for i, x := range r.Nowcast {
+		wet := x.IsWet(r.Units.Precipitation)
+		if wet && start < 0 {
+			start = i
+		} else if !wet && start >= 0 {
+			end = i
+			break
+		}
+	}
+	last := r.Nowcast[len(r.Nowcast)-1].Time
+	if start < 0 {
+		return fmt.Sprintf("No rain for the next %s", formatMinutes(last.Sub(now)))
+	}
+	wet := r.Nowcast[start:]
+	if end >= 0 {
+		wet = r.Nowcast[start:end]
+	}
+	noun := nowcastNoun(wet)
+	// Each step covers the preceding 15 minutes.
+	from := r.Nowcast[start].Time.Add(-15 * time.Minute).Sub(now)
+	if from <= 0 {
+		if end < 0 {
+			return fmt.Sprintf("%s for at least %s", noun, formatMinutes(last.Sub(now)))
+		}
+		return fmt.Sprintf("%s ending in %s", noun, formatMinutes(r.Nowcast[end].Time.Add(-15*time.Minute).Sub(now)))
+	}
+	if end < 0 {
+		return fmt.Sprintf("%s starting in %s", noun, formatMinutes(from))
+	}
+	to := r.Nowcast[end].Time.Add(-15 * time.Minute).Sub(now)
+	return fmt.Sprintf("%s starting in %s, ending in %s", noun, formatMinutes(from), formatMinutes(to))
+}
+
+// nowcastNoun returns the kind of precipitation of steps, e.g. "Snow" or "Rain and snow".
+// It is "Precipitation" when the weather codes do not tell or there are more than two kinds.
+func nowcastNoun(steps []NowcastStep) string {
+	var nouns []string
+	for _, x := range steps {
+		info, _ := weathercode.Lookup(x.WeatherCode)
+		if !info.Category.IsPrecipitation() {
+			return "Precipitation"
+		}
+		n := strings.TrimPrefix(info.Short, "heavy ")
+		if !slices.Contains(nouns, n) {
+			nouns = append(nouns, n)
+		}
+	}
+	var s string
+	switch len(nouns) {
+	case 1:
+		s = nouns[0]
+	case 2:
+		s = nouns[0] + " and " + nouns[1]
+	default:
+		return "Precipitation"
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func formatMinutes(d time.Duration) string {
+	m := int(d.Round(5 * time.Minute).Minutes())
+	if m < 5 {
+		m = 5
+	}
+	if m >= 60 && m%60 == 0 {
+		if m == 60 {
+			return "1 hour"
+		}
+		return fmt.Sprintf("%d hours", m/60)
+	}
+	return fmt.Sprintf("%d min", m)
+}
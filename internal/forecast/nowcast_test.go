@@ -0,0 +1,76 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+)
+
+// makeNowcast returns a result with one nowcast step per code starting 15 minutes after now.
+// A code of -1 is a dry step.
+func makeNowcast(now time.Time, codes ...int) Result {
+	var r Result
+	r.Units.Precipitation = "mm"
+	for i, c := range codes {
+		x := NowcastStep{Time: now.Add(time.Duration(i+1) * 15 * time.Minute), WeatherCode: c}
+		if c >= 0 {
+			x.Precipitation = 0.5
+		} else {
+			x.WeatherCode = 3
+		}
+		r.Nowcast = append(r.Nowcast, x)
+	}
+	return r
+}
+
+func TestNowcastText(t *testing.T) {
+	now := time.Date(2024, 11, 20, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name  string
+		codes []int
+		want  string
+	}{
+		{"none", []int{-1, -1, -1, -1, -1, -1, -1, -1}, "No rain for the next 2 hours"},
+		{"starting", []int{-1, -1, 61, 63, 63, 63, 63, 63}, "Rain starting in 30 min"},
+		{"starting and ending", []int{-1, 61, 65, -1, -1, -1, -1, -1}, "Rain starting in 15 min, ending in 45 min"},
+		{"ending", []int{61, 61, 61, -1, -1, -1, -1, -1}, "Rain ending in 45 min"},
+		{"continuous", []int{63, 63, 63, 63, 63, 63, 63, 63}, "Rain for at least 2 hours"},
+		{"snow", []int{-1, 71, 73, 75, -1, -1, -1, -1}, "Snow starting in 15 min, ending in 1 hour"},
+		{"drizzle", []int{-1, -1, -1, -1, 53, 53, 53, 53}, "Drizzle starting in 1 hour"},
+		{"showers", []int{80, 81, -1, -1, -1, -1, -1, -1}, "Showers ending in 30 min"},
+		{"thunderstorm", []int{-1, 95, 95, 95, 95, 95, 95, 95}, "Thunderstorm starting in 15 min"},
+		{"mixed", []int{-1, 61, 61, 71, 71, -1, -1, -1}, "Rain and snow starting in 15 min, ending in 75 min"},
+		{"more than two kinds", []int{61, 66, 71, -1, -1, -1, -1, -1}, "Precipitation ending in 45 min"},
+		{"no precipitation code", []int{-1, 3, 3, -1, -1, -1, -1, -1}, "Precipitation starting in 15 min, ending in 45 min"},
+		{"later steps ignored", []int{-1, 71, -1, 61, -1, -1, -1, -1}, "Snow starting in 15 min, ending in 30 min"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := makeNowcast(now, tc.codes...)
+			if got := r.NowcastText(now); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+	t.Run("empty", func(t *testing.T) {
+		if got := (Result{}).NowcastText(now); got != "" {
+			t.Errorf("got %q, want empty", got)
+		}
+	})
+}
+
+func TestGetNowcastWeatherCode(t *testing.T) {
+	response := makeResponse(true)
+	hourly := response["hourly"].(map[string][]any)
+	for i := range hourly["weather_code"] {
+		hourly["weather_code"][i] = 71.0
+	}
+	r, err := newTestClient(t, response).Get(52.52, 13.41)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, x := range r.Nowcast {
+		if x.WeatherCode != 71 {
+			t.Errorf("%s: got code %d, want 71", x.Time, x.WeatherCode)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package forecast
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ErikKalkoken/weatherapp/internal/weathercode"
+)
+
+// segment is a period of consecutive hours with the same category of weather.
+type segment struct {
+	category weathercode.Category
+	code     int // the most severe weather code
+	start    time.Time
+	hours    int
+}
+
+// add adds the hours of another segment.
+// The most severe code is kept when both segments have the same category,
+// so that the code is always consistent with the category of the segment.
+func (s *segment) add(o segment) {
+	if s.category == o.category {
+		s.code = moreSevere(s.code, o.code)
+	}
+	s.hours += o.hours
+}
+
+// moreSevere returns the weather code with the higher severity or intensity.
+// Returns a when both are equal.
+func moreSevere(a, b int) int {
+	x, _ := weathercode.Lookup(a)
+	y, _ := weathercode.Lookup(b)
+	if y.Severity > x.Severity || y.Severity == x.Severity && y.Intensity > x.Intensity {
+		return b
+	}
+	return a
+}
+
+func (s segment) isWet() bool {
+	return s.category.IsPrecipitation()
+}
+
+// isFair reports whether the sky of a segment is clear or only partly cloudy.
+func (s segment) isFair() bool {
+	return s.category == weathercode.CategoryClear || s.code == 2
+}
+
+// phrase returns how the weather of a segment is described.
+func (s segment) phrase() string {
+	switch {
+	case s.isWet():
+		return weathercode.Description(s.code)
+	case s.category == weathercode.CategoryClear:
+		return "clear"
+	case s.code == 2:
+		return "partly cloudy"
+	case s.category == weathercode.CategoryCloudy:
+		return "cloudy"
+	case s.category == weathercode.CategoryFog:
+		return "foggy"
+	}
+	return weathercode.Description(s.code)
+}
+
+const (
+	summaryHours    = 24 // number of hours covered by a summary
+	summarySegments = 4  // maximum number of segments in a summary
+)
+
+// Summary returns a short text describing the weather of the next 24 hours,
+// e.g. "Cloudy this morning, rain from around 3 pm, clearing overnight; warmer than yesterday."
+// Times are shown in the location of now.
+func (r Result) Summary(now time.Time) string {
+	hours := []ForecastHour{r.Current}
+	end := now.Add(summaryHours * time.Hour)
+	for _, h := range r.Hourly {
+		if h.Time.After(now) && !h.Time.After(end) {
+			hours = append(hours, h)
+		}
+	}
+	segments := makeSegments(hours)
+	if len(segments) > summarySegments {
+		segments = segments[:summarySegments]
+	}
+	var phrases []string
+	if len(segments) == 1 {
+		phrases = append(phrases, segments[0].phrase()+" for the next 24 hours")
+	} else {
+		for i, s := range segments {
+			start := s.start.In(now.Location())
+			var p string
+			switch {
+			case i == 0:
+				p = s.phrase() + " " + periodName(now, now)
+			case s.isWet() && segments[i-1].isWet():
+				p = fmt.Sprintf("turning to %s around %s", s.phrase(), start.Format("3 pm"))
+			case s.isWet():
+				p = fmt.Sprintf("%s from around %s", s.phrase(), start.Format("3 pm"))
+			case segments[i-1].isWet() && s.isFair():
+				p = "clearing " + periodName(start, now)
+			case segments[i-1].isWet():
+				p = fmt.Sprintf("dry from around %s", start.Format("3 pm"))
+			default:
+				p = s.phrase() + " " + periodName(start, now)
+			}
+			phrases = append(phrases, p)
+		}
+	}
+	s := strings.Join(phrases, ", ")
+	if t := r.temperatureTrend(hours); t != "" {
+		s += "; " + t
+	}
+	return strings.ToUpper(s[:1]) + s[1:] + "."
+}
+
+// makeSegments returns the hours grouped into segments by category of weather.
+// Short dry periods are merged into a neighbouring dry segment, so that the summary does not list every passing cloud.
+func makeSegments(hours []ForecastHour) []segment {
+	var segments []segment
+	for _, h := range hours {
+		x, _ := weathercode.Lookup(h.WeatherCode)
+		s := segment{category: x.Category, code: h.WeatherCode, start: h.Time, hours: 1}
+		if n := len(segments); n > 0 && segments[n-1].category == s.category {
+			segments[n-1].add(s)
+			continue
+		}
+		segments = append(segments, s)
+	}
+	merged := make([]segment, 0, len(segments))
+	for i, s := range segments {
+		n := len(merged)
+		short := s.hours < 3 && !s.isWet()
+		switch {
+		case n > 0 && merged[n-1].category == s.category, short && n > 0 && !merged[n-1].isWet():
+			merged[n-1].add(s)
+		case short && i+1 < len(segments) && !segments[i+1].isWet():
+			segments[i+1].start = s.start
+			segments[i+1].add(s)
+		default:
+			merged = append(merged, s)
+		}
+	}
+	return merged
+}
+
+// periodName returns the part of the day of t as seen from now, e.g. "this afternoon" or "overnight".
+func periodName(t, now time.Time) string {
+	t = t.In(now.Location())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	tomorrow := !t.Before(today.AddDate(0, 0, 1))
+	h := t.Hour()
+	var part string
+	switch {
+	case h >= 5 && h < 12:
+		part = "morning"
+	case h >= 12 && h < 18:
+		part = "afternoon"
+	case h >= 18 && h < 22:
+		part = "evening"
+	default:
+		if !tomorrow || h < 5 {
+			return "overnight"
+		}
+		part = "night"
+	}
+	if tomorrow {
+		return "tomorrow " + part
+	}
+	return "this " + part
+}
+
+// temperatureTrend compares the coming hours with the last 24 hours.
+// It returns an empty string when the past hours are not known.
+func (r Result) temperatureTrend(hours []ForecastHour) string {
+	if len(r.Past) == 0 || len(hours) == 0 {
+		return ""
+	}
+	maxTemperature := func(hh []ForecastHour) float64 {
+		x := hh[0].Temperature2m
+		for _, h := range hh {
+			x = max(x, h.Temperature2m)
+		}
+		return x
+	}
+	threshold := 2.0
+	if r.Units.Temperature == "°F" {
+		threshold *= 1.8
+	}
+	d := maxTemperature(hours) - maxTemperature(r.Past)
+	switch {
+	case d >= threshold:
+		return "warmer than yesterday"
+	case d <= -threshold:
+		return "colder than yesterday"
+	}
+	return "similar temperatures to yesterday"
+}
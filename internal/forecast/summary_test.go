@@ -0,0 +1,164 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ErikKalkoken/weatherapp/internal/weathercode"
+)
+
+var summaryNow = time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+
+// makeResult returns a result with the current hour at now and one forecast hour for each following code.
+func makeResult(now time.Time, codes ...int) Result {
+	r := Result{Current: ForecastHour{Time: now, WeatherCode: codes[0], IsCurrent: true}}
+	for i, c := range codes[1:] {
+		r.Hourly = append(r.Hourly, ForecastHour{Time: now.Add(time.Duration(i+1) * time.Hour), WeatherCode: c})
+	}
+	return r
+}
+
+// repeat returns a code n times.
+func repeat(code, n int) []int {
+	codes := make([]int, n)
+	for i := range codes {
+		codes[i] = code
+	}
+	return codes
+}
+
+func concat(codes ...[]int) []int {
+	var result []int
+	for _, c := range codes {
+		result = append(result, c...)
+	}
+	return result
+}
+
+func TestSummary(t *testing.T) {
+	cases := []struct {
+		name  string
+		codes []int
+		want  string
+	}{
+		{"one segment", repeat(0, 25), "Clear for the next 24 hours."},
+		{
+			"rain and clearing",
+			concat(repeat(3, 7), repeat(63, 3), repeat(0, 15)),
+			"Cloudy this morning, moderate rain from around 3 pm, clearing this evening.",
+		},
+		{
+			"turning to snow",
+			concat(repeat(61, 4), repeat(71, 21)),
+			"Slight rain this morning, turning to slight snow fall around 12 pm.",
+		},
+		{
+			"dry after rain",
+			concat(repeat(61, 4), repeat(3, 21)),
+			"Slight rain this morning, dry from around 12 pm.",
+		},
+		{
+			"most severe code by severity, not by number",
+			concat(repeat(0, 4), []int{80, 82, 85}, repeat(0, 18)),
+			"Clear this morning, violent rain showers from around 12 pm, clearing this afternoon.",
+		},
+		{
+			"freezing rain is less severe than heavy rain",
+			concat(repeat(65, 2), repeat(66, 23)),
+			"Heavy rain for the next 24 hours.",
+		},
+		{
+			"short dry period keeps code of its neighbour",
+			concat(repeat(2, 5), []int{45}, repeat(2, 19)),
+			"Partly cloudy for the next 24 hours.",
+		},
+		{
+			"short dry period before dry segment",
+			concat(repeat(61, 4), []int{0}, repeat(3, 20)),
+			"Slight rain this morning, dry from around 12 pm.",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := makeResult(summaryNow, tc.codes...).Summary(summaryNow)
+			if got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMakeSegments(t *testing.T) {
+	r := makeResult(summaryNow, concat(repeat(3, 4), []int{0}, repeat(2, 3), repeat(61, 2), []int{63, 51})...)
+	got := makeSegments(append([]ForecastHour{r.Current}, r.Hourly...))
+	// The short clear hour is merged into the cloudy hours around it
+	// and the more intense moderate rain wins over slight rain and drizzle.
+	want := []segment{
+		{category: weathercode.CategoryCloudy, code: 3, start: summaryNow, hours: 8},
+		{category: weathercode.CategoryRain, code: 63, start: summaryNow.Add(8 * time.Hour), hours: 4},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("segment %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSummaryTemperatureTrend(t *testing.T) {
+	cases := []struct {
+		name   string
+		unit   string
+		past   float64
+		coming float64
+		want   string
+	}{
+		{"warmer", "°C", 10, 15, "Clear for the next 24 hours; warmer than yesterday."},
+		{"colder", "°C", 10, 7, "Clear for the next 24 hours; colder than yesterday."},
+		{"similar", "°C", 10, 11.5, "Clear for the next 24 hours; similar temperatures to yesterday."},
+		{"similar in fahrenheit", "°F", 50, 53, "Clear for the next 24 hours; similar temperatures to yesterday."},
+		{"warmer in fahrenheit", "°F", 50, 54, "Clear for the next 24 hours; warmer than yesterday."},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := makeResult(summaryNow, repeat(0, 25)...)
+			r.Units.Temperature = tc.unit
+			r.Current.Temperature2m = tc.coming
+			r.Past = []ForecastHour{{Time: summaryNow.Add(-time.Hour), Temperature2m: tc.past}}
+			if got := r.Summary(summaryNow); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPeriodName(t *testing.T) {
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	cases := []struct {
+		t    time.Time
+		want string
+	}{
+		{time.Date(2024, 6, 1, 6, 0, 0, 0, time.UTC), "this morning"},
+		{time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC), "this afternoon"},
+		{time.Date(2024, 6, 1, 19, 0, 0, 0, time.UTC), "this evening"},
+		{time.Date(2024, 6, 1, 23, 0, 0, 0, time.UTC), "overnight"},
+		{time.Date(2024, 6, 2, 3, 0, 0, 0, time.UTC), "overnight"},
+		{time.Date(2024, 6, 2, 8, 0, 0, 0, time.UTC), "tomorrow morning"},
+		{time.Date(2024, 6, 2, 15, 0, 0, 0, time.UTC), "tomorrow afternoon"},
+		{time.Date(2024, 6, 2, 23, 0, 0, 0, time.UTC), "tomorrow night"},
+	}
+	for _, tc := range cases {
+		if got := periodName(tc.t, now); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.t.Format("Jan 2 15:04"), got, tc.want)
+		}
+	}
+	t.Run("in location of now", func(t *testing.T) {
+		berlin := time.FixedZone("CEST", 2*3600)
+		// 17:00 UTC is 19:00 in Berlin
+		if got := periodName(time.Date(2024, 6, 1, 17, 0, 0, 0, time.UTC), now.In(berlin)); got != "this evening" {
+			t.Errorf("got %q", got)
+		}
+	})
+}
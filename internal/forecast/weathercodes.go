@@ -0,0 +1,15 @@
+package forecast
+
+import (
+	"github.com/ErikKalkoken/weatherapp/internal/weathercode"
+)
+
+// Description returns a description of the weather.
+func (f ForecastHour) Description() string {
+	return weathercode.Description(f.WeatherCode)
+}
+
+// Description returns a description of the weather.
+func (f ForecastDay) Description() string {
+	return weathercode.Description(f.WeatherCode)
+}
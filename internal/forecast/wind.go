@@ -0,0 +1,10 @@
+package forecast
+
+import (
+	"github.com/ErikKalkoken/weatherapp/internal/wind"
+)
+
+// Beaufort returns the Beaufort number of the wind speed of an hour.
+func (h ForecastHour) Beaufort(units Units) int {
+	return wind.Beaufort(wind.ToKMH(h.WindSpeed10m, units.WindSpeed))
+}
@@ -0,0 +1,87 @@
+// Package ical exports daily forecasts as iCalendar feed (RFC 5545).
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	"github.com/ErikKalkoken/weatherapp/internal/forecast"
+	"github.com/ErikKalkoken/weatherapp/internal/location"
+)
+
+const maxLineLength = 75 // in octets, excluding the line break
+
+// Encode writes a calendar with one all-day event per forecasted day to w.
+func Encode(w io.Writer, loc location.Location, days []forecast.ForecastDay, now time.Time) error {
+	var b bytes.Buffer
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:-//ErikKalkoken//weatherapp//EN")
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	writeLine(&b, "X-WR-CALNAME:"+escape(fmt.Sprintf("Weather %s", loc.City)))
+	writeLine(&b, "REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	stamp := now.UTC().Format("20060102T150405Z")
+	title := cases.Title(language.English)
+	for _, d := range days {
+		day := d.Time.Format("20060102")
+		summary := fmt.Sprintf(
+			"%s %.0f°/%.0f° %d%%",
+			title.String(d.Description()),
+			d.Temperature2mMin,
+			d.Temperature2mMax,
+			d.PrecipitationProbabilityMean,
+		)
+		description := fmt.Sprintf(
+			"%s\nMin: %.0f°\nMax: %.0f°\nChance of precipitation: %d%%",
+			title.String(d.Description()),
+			d.Temperature2mMin,
+			d.Temperature2mMax,
+			d.PrecipitationProbabilityMean,
+		)
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, fmt.Sprintf("UID:%s-%.4f-%.4f@weatherapp", day, loc.Latitude, loc.Longitude))
+		writeLine(&b, "DTSTAMP:"+stamp)
+		writeLine(&b, "DTSTART;VALUE=DATE:"+day)
+		writeLine(&b, "DTEND;VALUE=DATE:"+d.Time.AddDate(0, 0, 1).Format("20060102"))
+		writeLine(&b, "SUMMARY:"+escape(summary))
+		writeLine(&b, "DESCRIPTION:"+escape(description))
+		if loc.City != "" {
+			writeLine(&b, "LOCATION:"+escape(fmt.Sprintf("%s, %s", loc.City, loc.Country)))
+		}
+		writeLine(&b, fmt.Sprintf("GEO:%f;%f", loc.Latitude, loc.Longitude))
+		writeLine(&b, "TRANSP:TRANSPARENT")
+		writeLine(&b, "END:VEVENT")
+	}
+	writeLine(&b, "END:VCALENDAR")
+	_, err := b.WriteTo(w)
+	return err
+}
+
+// writeLine writes a content line and folds it when it is too long.
+func writeLine(b *bytes.Buffer, s string) {
+	var n int
+	for _, r := range s {
+		size := len(string(r))
+		if n+size > maxLineLength {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	b.WriteString("\r\n")
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// escape escapes a text value.
+func escape(s string) string {
+	return escaper.Replace(s)
+}
@@ -0,0 +1,112 @@
+// Package iconpack loads sets of weather icons from disk.
+//
+// An icon pack is a directory with image files and a manifest.toml, which maps icon names to files:
+//
+//	name = "Material"
+//	monochrome = true
+//
+//	[icons]
+//	sunny = "sunny.svg"
+//	night = "moon.png"
+//
+// Icon names are the names defined in the weathercode package, e.g. "partly-cloudy".
+package iconpack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/ErikKalkoken/weatherapp/internal/weathercode"
+)
+
+// ManifestName is the file name of the manifest of an icon pack.
+const ManifestName = "manifest.toml"
+
+// Image is an image file of an icon pack.
+type Image struct {
+	Name    string // file name, e.g. "sunny.svg"
+	Content []byte
+}
+
+// IsSVG reports whether an image is an SVG file.
+func (x Image) IsSVG() bool {
+	return strings.EqualFold(filepath.Ext(x.Name), ".svg")
+}
+
+// Pack is a set of weather icons.
+// Packs do not need to have an image for every icon.
+type Pack struct {
+	Name        string
+	Description string
+	Monochrome  bool // monochrome SVG images are coloured by the theme
+	Icons       map[weathercode.Icon]Image
+}
+
+type manifest struct {
+	Name        string            `toml:"name"`
+	Description string            `toml:"description"`
+	Monochrome  bool              `toml:"monochrome"`
+	Icons       map[string]string `toml:"icons"`
+}
+
+// Load returns the icon pack in a directory.
+func Load(dir string) (*Pack, error) {
+	path := filepath.Join(dir, ManifestName)
+	var m manifest
+	md, err := toml.DecodeFile(path, &m)
+	if err != nil {
+		return nil, fmt.Errorf("icon pack %s: %w", dir, err)
+	}
+	if keys := md.Undecoded(); len(keys) > 0 {
+		return nil, fmt.Errorf("icon pack %s: unknown key in manifest: %s", dir, keys[0])
+	}
+	p := &Pack{
+		Name:        m.Name,
+		Description: m.Description,
+		Monochrome:  m.Monochrome,
+		Icons:       make(map[weathercode.Icon]Image),
+	}
+	if p.Name == "" {
+		p.Name = filepath.Base(dir)
+	}
+	known := weathercode.Icons()
+	for name, file := range m.Icons {
+		icon := weathercode.Icon(name)
+		if !slices.Contains(known, icon) {
+			return nil, fmt.Errorf("icon pack %s: unknown icon: %s", dir, name)
+		}
+		if ext := strings.ToLower(filepath.Ext(file)); ext != ".svg" && ext != ".png" {
+			return nil, fmt.Errorf("icon pack %s: unsupported file type for icon %s: %s", dir, name, file)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			return nil, fmt.Errorf("icon pack %s: icon %s: %w", dir, name, err)
+		}
+		p.Icons[icon] = Image{Name: filepath.Base(file), Content: data}
+	}
+	return p, nil
+}
+
+// List returns the names of the icon packs in a directory, which can be loaded with [Load].
+// Each sub directory with a manifest is an icon pack.
+func List(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, e.Name(), ManifestName)); err == nil {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
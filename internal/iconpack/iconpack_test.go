@@ -0,0 +1,87 @@
+package iconpack
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ErikKalkoken/weatherapp/internal/weathercode"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("monochrome", func(t *testing.T) {
+		p, err := Load("testdata/packs/mono")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Name != "Mono" || !p.Monochrome {
+			t.Errorf("got %+v", p)
+		}
+		if len(p.Icons) != 2 {
+			t.Fatalf("got %d icons, want 2", len(p.Icons))
+		}
+		sunny := p.Icons[weathercode.IconSunny]
+		if sunny.Name != "sunny.svg" || !sunny.IsSVG() || len(sunny.Content) == 0 {
+			t.Errorf("sunny: got %s, svg: %v, %d bytes", sunny.Name, sunny.IsSVG(), len(sunny.Content))
+		}
+		night := p.Icons[weathercode.IconNight]
+		if night.Name != "night.png" || night.IsSVG() || !strings.HasPrefix(string(night.Content), "\x89PNG") {
+			t.Errorf("night: got %s, svg: %v", night.Name, night.IsSVG())
+		}
+	})
+	t.Run("colored", func(t *testing.T) {
+		p, err := Load("testdata/packs/color")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Name != "Color" || p.Description != "Colored icons" || p.Monochrome {
+			t.Errorf("got %+v", p)
+		}
+		if p.Icons[weathercode.IconSunny].IsSVG() || !p.Icons[weathercode.IconRainy].IsSVG() {
+			t.Errorf("got icons %v", p.Icons)
+		}
+	})
+	cases := []struct {
+		dir  string
+		want string
+	}{
+		{"nomanifest", "manifest.toml"},
+		{"badtoml", "icon pack testdata/broken/badtoml"},
+		{"unknownkey", "unknown key in manifest: color"},
+		{"unknownicon", "unknown icon: sunshine"},
+		{"missingfile", "icon sunny"},
+		{"badtype", "unsupported file type for icon sunny: sunny.gif"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.dir, func(t *testing.T) {
+			_, err := Load("testdata/broken/" + tc.dir)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("got %q, want %q", err, tc.want)
+			}
+		})
+	}
+	t.Run("missing file is reported as not exist", func(t *testing.T) {
+		_, err := Load("testdata/broken/missingfile")
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("got %v", err)
+		}
+	})
+}
+
+func TestList(t *testing.T) {
+	names, err := List("testdata/packs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"color", "mono"}; !slices.Equal(names, want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+	if _, err := List("testdata/missing"); err == nil {
+		t.Error("missing directory: expected error")
+	}
+}
@@ -1,17 +1,45 @@
-// Package location allows to determine the current location of a machine.
+// Package location allows to determine the current location of a machine
+// and to look up locations by name.
 package location
 
 import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+
+	"github.com/ErikKalkoken/weatherapp/internal/apierror"
+)
+
+// Names of the APIs in errors.
+const (
+	geocodingAPIName = "Open-Meteo geocoding API"
+	ipAPIName        = "IP API"
 )
 
 type Location struct {
-	City      string
-	Country   string
-	Latitude  float64
-	Longitude float64
+	City      string  `json:"city"`
+	Country   string  `json:"country"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Client is a client for looking up locations.
+type Client struct {
+	GeocodingURL string // base URL of the Open-Meteo geocoding API
+	IPURL        string // URL of the IP API
+
+	httpClient *http.Client
+}
+
+// NewClient returns a new client with default settings.
+func NewClient(httpClient *http.Client) *Client {
+	c := &Client{
+		GeocodingURL: "https://geocoding-api.open-meteo.com/v1/search",
+		IPURL:        "http://ip-api.com/json/",
+		httpClient:   httpClient,
+	}
+	return c
 }
 
 type ipResponse struct {
@@ -28,24 +56,24 @@ type ipResponse struct {
 	Zip         string
 }
 
-// Get returns the location associated with the IP address of this machine.
-func Get(client *http.Client) (loc Location, err error) {
-	resp, err := client.Get("http://ip-api.com/json/")
+// Current returns the location associated with the IP address of this machine.
+func (c *Client) Current() (loc Location, err error) {
+	resp, err := c.httpClient.Get(c.IPURL)
 	if err != nil {
-		return Location{}, fmt.Errorf("making request to IP API: %w", err)
+		return Location{}, &apierror.NetworkError{API: ipAPIName, Err: err}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return Location{}, fmt.Errorf("making request to IP API %s: %w", resp.Status, err)
+		return Location{}, &apierror.HTTPStatusError{API: ipAPIName, StatusCode: resp.StatusCode, Status: resp.Status}
 	}
 
 	var response ipResponse
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return Location{}, fmt.Errorf("decoding response: %w", err)
+		return Location{}, &apierror.MalformedValueError{Field: "response", Err: err}
 	}
 	if response.Status == "fail" {
-		return Location{}, fmt.Errorf(response.Message)
+		return Location{}, &apierror.APIError{API: ipAPIName, Reason: response.Message}
 	}
 	l := Location{
 		Latitude:  response.Lat,
@@ -55,3 +83,52 @@ func Get(client *http.Client) (loc Location, err error) {
 	}
 	return l, nil
 }
+
+type geocodingResponse struct {
+	Error   bool
+	Reason  string
+	Results []struct {
+		Name      string
+		Country   string
+		Latitude  float64
+		Longitude float64
+	}
+}
+
+// Search returns the best matching location for a name, e.g. a city.
+func (c *Client) Search(name string) (Location, error) {
+	v := url.Values{}
+	v.Add("name", name)
+	v.Add("count", "1")
+	v.Add("format", "json")
+	resp, err := c.httpClient.Get(c.GeocodingURL + "?" + v.Encode())
+	if err != nil {
+		return Location{}, &apierror.NetworkError{API: geocodingAPIName, Err: err}
+	}
+	defer resp.Body.Close()
+
+	var response geocodingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		if resp.StatusCode >= 400 {
+			return Location{}, &apierror.HTTPStatusError{API: geocodingAPIName, StatusCode: resp.StatusCode, Status: resp.Status}
+		}
+		return Location{}, &apierror.MalformedValueError{Field: "response", Err: err}
+	}
+	if response.Error {
+		return Location{}, &apierror.APIError{API: geocodingAPIName, Reason: response.Reason}
+	}
+	if resp.StatusCode >= 400 {
+		return Location{}, &apierror.HTTPStatusError{API: geocodingAPIName, StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	if len(response.Results) == 0 {
+		return Location{}, fmt.Errorf("location %s: %w", name, apierror.ErrNotFound)
+	}
+	r := response.Results[0]
+	l := Location{
+		City:      r.Name,
+		Country:   r.Country,
+		Latitude:  r.Latitude,
+		Longitude: r.Longitude,
+	}
+	return l, nil
+}
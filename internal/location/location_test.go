@@ -0,0 +1,134 @@
+package location
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ErikKalkoken/weatherapp/internal/apierror"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	c := NewClient(srv.Client())
+	c.GeocodingURL = srv.URL
+	c.IPURL = srv.URL
+	return c
+}
+
+func respond(status int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}
+}
+
+func closedServerClient() *Client {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	c := NewClient(http.DefaultClient)
+	c.GeocodingURL = srv.URL
+	c.IPURL = srv.URL
+	return c
+}
+
+func TestCurrent(t *testing.T) {
+	c := newTestClient(t, respond(http.StatusOK, `{"status":"success","city":"Berlin","country":"Germany","lat":52.52,"lon":13.41}`))
+	got, err := c.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Location{City: "Berlin", Country: "Germany", Latitude: 52.52, Longitude: 13.41}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestCurrentErrors(t *testing.T) {
+	t.Run("network", func(t *testing.T) {
+		_, err := closedServerClient().Current()
+		var e *apierror.NetworkError
+		if !errors.Is(err, apierror.ErrNetwork) || !errors.As(err, &e) || e.API != ipAPIName {
+			t.Errorf("got %#v", err)
+		}
+	})
+	t.Run("HTTP status", func(t *testing.T) {
+		_, err := newTestClient(t, respond(http.StatusTooManyRequests, "")).Current()
+		var e *apierror.HTTPStatusError
+		if !errors.Is(err, apierror.ErrHTTPStatus) || !errors.As(err, &e) || e.StatusCode != http.StatusTooManyRequests {
+			t.Errorf("got %#v", err)
+		}
+	})
+	t.Run("API error body", func(t *testing.T) {
+		_, err := newTestClient(t, respond(http.StatusOK, `{"status":"fail","message":"reserved range"}`)).Current()
+		var e *apierror.APIError
+		if !errors.Is(err, apierror.ErrAPI) || !errors.As(err, &e) || e.Reason != "reserved range" {
+			t.Errorf("got %#v", err)
+		}
+	})
+	t.Run("malformed", func(t *testing.T) {
+		_, err := newTestClient(t, respond(http.StatusOK, "<html>")).Current()
+		var e *apierror.MalformedValueError
+		if !errors.Is(err, apierror.ErrMalformedValue) || !errors.As(err, &e) || e.Field != "response" {
+			t.Errorf("got %#v", err)
+		}
+	})
+}
+
+func TestSearch(t *testing.T) {
+	var query string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("name")
+		respond(http.StatusOK, `{"results":[{"name":"Berlin","country":"Germany","latitude":52.52,"longitude":13.41}]}`)(w, r)
+	})
+	got, err := c.Search("Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query != "Berlin" {
+		t.Errorf("got query %q", query)
+	}
+	want := Location{City: "Berlin", Country: "Germany", Latitude: 52.52, Longitude: 13.41}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestSearchErrors(t *testing.T) {
+	t.Run("network", func(t *testing.T) {
+		_, err := closedServerClient().Search("Berlin")
+		var e *apierror.NetworkError
+		if !errors.Is(err, apierror.ErrNetwork) || !errors.As(err, &e) || e.API != geocodingAPIName {
+			t.Errorf("got %#v", err)
+		}
+	})
+	t.Run("HTTP status", func(t *testing.T) {
+		_, err := newTestClient(t, respond(http.StatusBadGateway, "bad gateway")).Search("Berlin")
+		var e *apierror.HTTPStatusError
+		if !errors.Is(err, apierror.ErrHTTPStatus) || !errors.As(err, &e) || e.StatusCode != http.StatusBadGateway {
+			t.Errorf("got %#v", err)
+		}
+	})
+	t.Run("API error body", func(t *testing.T) {
+		_, err := newTestClient(t, respond(http.StatusBadRequest, `{"error":true,"reason":"Parameter count must be between 1 and 100."}`)).Search("Berlin")
+		var e *apierror.APIError
+		if !errors.Is(err, apierror.ErrAPI) || !errors.As(err, &e) || e.Reason != "Parameter count must be between 1 and 100." {
+			t.Errorf("got %#v", err)
+		}
+	})
+	t.Run("malformed", func(t *testing.T) {
+		_, err := newTestClient(t, respond(http.StatusOK, "<html>")).Search("Berlin")
+		if !errors.Is(err, apierror.ErrMalformedValue) {
+			t.Errorf("got %#v", err)
+		}
+	})
+	t.Run("not found", func(t *testing.T) {
+		_, err := newTestClient(t, respond(http.StatusOK, `{}`)).Search("Nowhere")
+		if !errors.Is(err, apierror.ErrNotFound) {
+			t.Errorf("got %#v", err)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+// Package metrics exports weather values and fetch health as Prometheus metrics.
+package metrics
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/ErikKalkoken/weatherapp/internal/apierror"
+	"github.com/ErikKalkoken/weatherapp/internal/weather"
+)
+
+const namespace = "weatherapp"
+
+// Metrics records metrics for a weather service. It implements [weather.Observer].
+type Metrics struct {
+	registry *prometheus.Registry
+
+	temperature              *prometheus.GaugeVec
+	precipitationProbability *prometheus.GaugeVec
+	weatherCode              *prometheus.GaugeVec
+
+	fetchDuration   prometheus.Histogram
+	fetchSuccess    *prometheus.CounterVec
+	fetchErrors     *prometheus.CounterVec
+	lastSuccess     prometheus.Gauge
+	locationLookups prometheus.Counter
+}
+
+func New() *Metrics {
+	locationLabels := []string{"city", "country"}
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		temperature: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "temperature_degrees",
+			Help:      "Current temperature at 2m in the configured unit.",
+		}, locationLabels),
+		precipitationProbability: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "precipitation_probability_percent",
+			Help:      "Current probability of precipitation.",
+		}, locationLabels),
+		weatherCode: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "weather_code",
+			Help:      "Current WMO weather code.",
+		}, locationLabels),
+		fetchDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "forecast_fetch_duration_seconds",
+			Help:      "Duration of requests to the forecast API.",
+			Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		}),
+		fetchSuccess: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "fetch_success_total",
+			Help:      "Number of successful fetches by source.",
+		}, []string{"source"}),
+		fetchErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "fetch_errors_total",
+			Help:      "Number of failed fetches by source and kind of error.",
+		}, []string{"source", "kind"}),
+		lastSuccess: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "last_success_timestamp_seconds",
+			Help:      "Unix time of the last successful refresh.",
+		}),
+		locationLookups: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "location_lookups_total",
+			Help:      "Number of lookups of the current location.",
+		}),
+	}
+	m.registry.MustRegister(
+		m.temperature,
+		m.precipitationProbability,
+		m.weatherCode,
+		m.fetchDuration,
+		m.fetchSuccess,
+		m.fetchErrors,
+		m.lastSuccess,
+		m.locationLookups,
+	)
+	return m
+}
+
+// Handler returns an HTTP handler for serving the metrics.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) LocationFetched(err error) {
+	m.locationLookups.Inc()
+	m.recordFetch("location", err)
+}
+
+func (m *Metrics) ForecastFetched(d time.Duration, err error) {
+	m.fetchDuration.Observe(d.Seconds())
+	m.recordFetch("forecast", err)
+}
+
+func (m *Metrics) SnapshotUpdated(x weather.Snapshot) {
+	m.lastSuccess.Set(float64(x.UpdatedAt.Unix()))
+	for _, g := range []*prometheus.GaugeVec{m.temperature, m.precipitationProbability, m.weatherCode} {
+		g.Reset()
+	}
+	labels := prometheus.Labels{"city": x.Location.City, "country": x.Location.Country}
+	c := x.Forecast.Current
+	m.temperature.With(labels).Set(c.Temperature2m)
+	m.precipitationProbability.With(labels).Set(float64(c.PrecipitationProbability))
+	m.weatherCode.With(labels).Set(float64(c.WeatherCode))
+}
+
+func (m *Metrics) recordFetch(source string, err error) {
+	if err != nil {
+		m.fetchErrors.WithLabelValues(source, errorKind(err)).Inc()
+		return
+	}
+	m.fetchSuccess.WithLabelValues(source).Inc()
+}
+
+// errorKind returns a short classification of a fetch error.
+func errorKind(err error) string {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return "timeout"
+		}
+		return "network"
+	}
+	switch {
+	case errors.Is(err, apierror.ErrNetwork):
+		return "network"
+	case errors.Is(err, apierror.ErrHTTPStatus):
+		return "http"
+	case errors.Is(err, apierror.ErrAPI):
+		return "api"
+	case errors.Is(err, apierror.ErrMissingField), errors.Is(err, apierror.ErrMalformedValue):
+		return "data"
+	}
+	return "other"
+}
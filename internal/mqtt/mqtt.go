@@ -0,0 +1,193 @@
+// Package mqtt publishes weather data to an MQTT broker.
+//
+// All messages are retained, so that new subscribers get the latest weather immediately.
+// Optionally, Home Assistant MQTT discovery messages are published,
+// which let the sensors appear in Home Assistant automatically.
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/ErikKalkoken/weatherapp/internal/forecast"
+	"github.com/ErikKalkoken/weatherapp/internal/weather"
+)
+
+const (
+	hourlyHours    = 48 // number of hours published in the hourly forecast
+	publishTimeout = 10 * time.Second
+	qos            = 1
+)
+
+// Config is the configuration for a [Publisher].
+type Config struct {
+	Broker          string // URL of the broker, e.g. tcp://localhost:1883
+	ClientID        string
+	Username        string
+	Password        string
+	TopicPrefix     string // Prefix for all weather topics, e.g. weatherapp
+	DiscoveryPrefix string // Prefix for Home Assistant discovery. Discovery is disabled when empty.
+}
+
+// broker publishes messages to an MQTT broker.
+type broker interface {
+	publish(topic string, retained bool, payload []byte) error
+	close()
+}
+
+// pahoBroker is a broker connected with the paho MQTT client.
+type pahoBroker struct {
+	client paho.Client
+}
+
+func (b pahoBroker) publish(topic string, retained bool, payload []byte) error {
+	t := b.client.Publish(topic, qos, retained, payload)
+	if !t.WaitTimeout(publishTimeout) {
+		return fmt.Errorf("publishing to %s: timeout", topic)
+	}
+	if err := t.Error(); err != nil {
+		return fmt.Errorf("publishing to %s: %w", topic, err)
+	}
+	return nil
+}
+
+func (b pahoBroker) close() {
+	b.client.Disconnect(250)
+}
+
+// Publisher publishes weather snapshots to an MQTT broker.
+type Publisher struct {
+	broker     broker
+	cfg        Config
+	discovered atomic.Bool // whether discovery messages have been published for the current connection
+}
+
+// New returns a new publisher, which is connected to the broker.
+func New(cfg Config) (*Publisher, error) {
+	if cfg.ClientID == "" {
+		cfg.ClientID = "weatherapp"
+	}
+	cfg.TopicPrefix = strings.TrimSuffix(cfg.TopicPrefix, "/")
+	p := &Publisher{cfg: cfg}
+	opts := paho.NewClientOptions().
+		AddBroker(cfg.Broker).
+		SetClientID(cfg.ClientID).
+		SetUsername(cfg.Username).
+		SetPassword(cfg.Password).
+		SetAutoReconnect(true).
+		SetOnConnectHandler(func(paho.Client) {
+			p.discovered.Store(false)
+		})
+	client := paho.NewClient(opts)
+	t := client.Connect()
+	if !t.WaitTimeout(publishTimeout) {
+		return nil, fmt.Errorf("connecting to MQTT broker %s: timeout", cfg.Broker)
+	}
+	if err := t.Error(); err != nil {
+		return nil, fmt.Errorf("connecting to MQTT broker %s: %w", cfg.Broker, err)
+	}
+	p.broker = pahoBroker{client}
+	return p, nil
+}
+
+// Close disconnects from the broker.
+func (p *Publisher) Close() {
+	p.broker.close()
+}
+
+// Publish publishes the current weather and the forecasts of a snapshot.
+// The hourly forecast is limited to the next 48 hours.
+func (p *Publisher) Publish(x weather.Snapshot) error {
+	if !p.discovered.Load() {
+		if err := p.publishDiscovery(x.Forecast.Units); err != nil {
+			return err
+		}
+		p.discovered.Store(true)
+	}
+	messages := map[string]any{
+		"location": x.Location,
+		"current":  x.Forecast.Current,
+		"hourly":   x.Forecast.Hourly[:min(len(x.Forecast.Hourly), hourlyHours)],
+		"daily":    x.Forecast.Daily,
+	}
+	for name, v := range messages {
+		if err := p.publishJSON(p.cfg.TopicPrefix+"/"+name, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Observer returns an observer for publishing each updated snapshot of a weather service.
+func (p *Publisher) Observer() weather.Observer {
+	return weather.SnapshotFunc(func(x weather.Snapshot) {
+		if err := p.Publish(x); err != nil {
+			log.Printf("ERROR: publishing to MQTT: %s", err)
+		}
+	})
+}
+
+type sensor struct {
+	id          string
+	name        string
+	field       string
+	unit        string
+	deviceClass string
+}
+
+func makeSensors(units forecast.Units) []sensor {
+	return []sensor{
+		{"temperature", "Temperature", "temperature_2m", units.Temperature, "temperature"},
+		{"precipitation_probability", "Precipitation probability", "precipitation_probability", "%", ""},
+		{"weather_code", "Weather code", "weather_code", "", ""},
+	}
+}
+
+// publishDiscovery publishes Home Assistant discovery messages for all sensors.
+func (p *Publisher) publishDiscovery(units forecast.Units) error {
+	if p.cfg.DiscoveryPrefix == "" {
+		return nil
+	}
+	nodeID := strings.ReplaceAll(p.cfg.TopicPrefix, "/", "_")
+	device := map[string]any{
+		"identifiers": []string{nodeID},
+		"name":        "Weather",
+		"model":       "weatherapp",
+	}
+	for _, s := range makeSensors(units) {
+		payload := map[string]any{
+			"name":                  s.name,
+			"unique_id":             nodeID + "_" + s.id,
+			"state_topic":           p.cfg.TopicPrefix + "/current",
+			"value_template":        fmt.Sprintf("{{ value_json.%s }}", s.field),
+			"json_attributes_topic": p.cfg.TopicPrefix + "/location",
+			"device":                device,
+		}
+		if s.unit != "" {
+			payload["unit_of_measurement"] = s.unit
+			payload["state_class"] = "measurement"
+		}
+		if s.deviceClass != "" {
+			payload["device_class"] = s.deviceClass
+		}
+		topic := fmt.Sprintf("%s/sensor/%s/%s/config", p.cfg.DiscoveryPrefix, nodeID, s.id)
+		if err := p.publishJSON(topic, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Publisher) publishJSON(topic string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return p.broker.publish(topic, true, data)
+}
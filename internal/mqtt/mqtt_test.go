@@ -0,0 +1,200 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/ErikKalkoken/weatherapp/internal/forecast"
+	"github.com/ErikKalkoken/weatherapp/internal/location"
+	"github.com/ErikKalkoken/weatherapp/internal/weather"
+)
+
+type message struct {
+	topic    string
+	retained bool
+	payload  []byte
+}
+
+// fakeBroker records published messages.
+type fakeBroker struct {
+	messages []message
+}
+
+func (b *fakeBroker) publish(topic string, retained bool, payload []byte) error {
+	b.messages = append(b.messages, message{topic, retained, payload})
+	return nil
+}
+
+func (b *fakeBroker) close() {}
+
+func (b *fakeBroker) topics() []string {
+	var topics []string
+	for _, m := range b.messages {
+		topics = append(topics, m.topic)
+	}
+	slices.Sort(topics)
+	return topics
+}
+
+func (b *fakeBroker) find(topic string) (message, bool) {
+	for _, m := range b.messages {
+		if m.topic == topic {
+			return m, true
+		}
+	}
+	return message{}, false
+}
+
+func makeSnapshot() weather.Snapshot {
+	now := time.Date(2024, 11, 20, 10, 0, 0, 0, time.UTC)
+	var hourly []forecast.ForecastHour
+	for i := range 72 {
+		hourly = append(hourly, forecast.ForecastHour{Time: now.Add(time.Duration(i) * time.Hour), Temperature2m: float64(i)})
+	}
+	return weather.Snapshot{
+		Location: location.Location{City: "Berlin", Latitude: 52.52, Longitude: 13.41},
+		Forecast: forecast.Result{
+			Current: forecast.ForecastHour{Time: now, Temperature2m: 12.5, WeatherCode: 3},
+			Hourly:  hourly,
+			Daily:   []forecast.ForecastDay{{}, {}},
+			Units:   forecast.Units{Temperature: "°C"},
+		},
+		UpdatedAt: now,
+	}
+}
+
+func TestPublish(t *testing.T) {
+	b := &fakeBroker{}
+	p := &Publisher{broker: b, cfg: Config{TopicPrefix: "weather", DiscoveryPrefix: "homeassistant"}}
+	if err := p.Publish(makeSnapshot()); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"homeassistant/sensor/weather/precipitation_probability/config",
+		"homeassistant/sensor/weather/temperature/config",
+		"homeassistant/sensor/weather/weather_code/config",
+		"weather/current",
+		"weather/daily",
+		"weather/hourly",
+		"weather/location",
+	}
+	if got := b.topics(); !slices.Equal(got, want) {
+		t.Errorf("got topics %v, want %v", got, want)
+	}
+	for _, m := range b.messages {
+		if !m.retained {
+			t.Errorf("%s: not retained", m.topic)
+		}
+	}
+	t.Run("current", func(t *testing.T) {
+		m, _ := b.find("weather/current")
+		var v map[string]any
+		if err := json.Unmarshal(m.payload, &v); err != nil {
+			t.Fatal(err)
+		}
+		if v["temperature_2m"] != 12.5 || v["weather_code"] != 3.0 {
+			t.Errorf("got %v", v)
+		}
+	})
+	t.Run("hourly is limited", func(t *testing.T) {
+		m, _ := b.find("weather/hourly")
+		var v []forecast.ForecastHour
+		if err := json.Unmarshal(m.payload, &v); err != nil {
+			t.Fatal(err)
+		}
+		if len(v) != hourlyHours {
+			t.Errorf("got %d hours, want %d", len(v), hourlyHours)
+		}
+		if v[0].Temperature2m != 0 {
+			t.Errorf("first hour: got %+v", v[0])
+		}
+	})
+	t.Run("discovery", func(t *testing.T) {
+		m, _ := b.find("homeassistant/sensor/weather/temperature/config")
+		var v map[string]any
+		if err := json.Unmarshal(m.payload, &v); err != nil {
+			t.Fatal(err)
+		}
+		if v["state_topic"] != "weather/current" || v["unit_of_measurement"] != "°C" || v["unique_id"] != "weather_temperature" {
+			t.Errorf("got %v", v)
+		}
+	})
+	t.Run("discovery only once per connection", func(t *testing.T) {
+		b.messages = nil
+		if err := p.Publish(makeSnapshot()); err != nil {
+			t.Fatal(err)
+		}
+		if got := len(b.messages); got != 4 {
+			t.Errorf("got %d messages, want 4", got)
+		}
+	})
+}
+
+func TestPublishWithoutDiscovery(t *testing.T) {
+	b := &fakeBroker{}
+	p := &Publisher{broker: b, cfg: Config{TopicPrefix: "weather"}}
+	if err := p.Publish(makeSnapshot()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := b.topics(), []string{"weather/current", "weather/daily", "weather/hourly", "weather/location"}; !slices.Equal(got, want) {
+		t.Errorf("got topics %v, want %v", got, want)
+	}
+}
+
+// TestBroker publishes to a real broker and subscribes to the messages.
+// It only runs when WEATHERAPP_MQTT_TEST_BROKER is set to the URL of a broker, e.g. tcp://localhost:1883.
+func TestBroker(t *testing.T) {
+	url := os.Getenv("WEATHERAPP_MQTT_TEST_BROKER")
+	if url == "" {
+		t.Skip("WEATHERAPP_MQTT_TEST_BROKER not set")
+	}
+	prefix := fmt.Sprintf("weatherapp-test/%d", time.Now().UnixNano())
+	p, err := New(Config{Broker: url, ClientID: "weatherapp-test-publisher", TopicPrefix: prefix})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	if err := p.Publish(makeSnapshot()); err != nil {
+		t.Fatal(err)
+	}
+
+	received := make(chan paho.Message, 10)
+	sub := paho.NewClient(paho.NewClientOptions().AddBroker(url).SetClientID("weatherapp-test-subscriber"))
+	if tk := sub.Connect(); !tk.WaitTimeout(publishTimeout) || tk.Error() != nil {
+		t.Fatalf("connecting subscriber: %v", tk.Error())
+	}
+	defer sub.Disconnect(250)
+	tk := sub.Subscribe(prefix+"/#", qos, func(_ paho.Client, m paho.Message) {
+		received <- m
+	})
+	if !tk.WaitTimeout(publishTimeout) || tk.Error() != nil {
+		t.Fatalf("subscribing: %v", tk.Error())
+	}
+	var topics []string
+	for range 4 {
+		select {
+		case m := <-received:
+			if !m.Retained() {
+				t.Errorf("%s: not retained", m.Topic())
+			}
+			topics = append(topics, m.Topic())
+		case <-time.After(publishTimeout):
+			t.Fatalf("timeout: received %v", topics)
+		}
+	}
+	slices.Sort(topics)
+	want := []string{prefix + "/current", prefix + "/daily", prefix + "/hourly", prefix + "/location"}
+	if !slices.Equal(topics, want) {
+		t.Errorf("got topics %v, want %v", topics, want)
+	}
+	// remove the retained messages
+	for _, topic := range want {
+		sub.Publish(topic, qos, true, []byte{}).WaitTimeout(publishTimeout)
+	}
+}
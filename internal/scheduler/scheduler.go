@@ -0,0 +1,181 @@
+// Package scheduler decides when to refresh the weather.
+//
+// The scheduler refreshes on startup and then periodically.
+// It refreshes less often while the app is hidden or when refreshes fail repeatedly.
+// It also refreshes right away after the machine resumes from sleep
+// or when the network configuration changes, e.g. after reconnecting to a network.
+// Refreshes never overlap.
+package scheduler
+
+import (
+	"log"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	checkInterval = 30 * time.Second // how often to check for wake up and network changes
+	hiddenFactor  = 4                // how much slower to refresh while hidden
+	maxBackoff    = 8                // maximum delay after failed refreshes as multiple of the interval
+)
+
+// Scheduler calls a refresh function at adaptive intervals.
+type Scheduler struct {
+	interval time.Duration
+	refresh  func() error
+
+	done              chan struct{}
+	started           atomic.Bool
+	stop              chan struct{}
+	stopOnce          sync.Once
+	trigger           chan struct{}
+	visibilityChanged chan struct{}
+	visible           atomic.Bool
+
+	// only accessed from the run loop
+	errorCount  int
+	lastAttempt time.Time
+	lastSuccess time.Time
+}
+
+// New returns a new scheduler, which calls refresh about every interval while visible.
+func New(refresh func() error, interval time.Duration) *Scheduler {
+	s := &Scheduler{
+		done:              make(chan struct{}),
+		interval:          interval,
+		refresh:           refresh,
+		stop:              make(chan struct{}),
+		trigger:           make(chan struct{}, 1),
+		visibilityChanged: make(chan struct{}, 1),
+	}
+	s.visible.Store(true)
+	return s
+}
+
+// Start starts the scheduler. It refreshes immediately.
+func (s *Scheduler) Start() {
+	if s.started.Swap(true) {
+		return
+	}
+	go s.run()
+}
+
+// Stop stops the scheduler and waits for a running refresh to complete.
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	if s.started.Load() {
+		<-s.done
+	}
+}
+
+// Trigger requests a refresh as soon as possible.
+func (s *Scheduler) Trigger() {
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// SetVisible informs the scheduler whether the app is currently visible to the user.
+func (s *Scheduler) SetVisible(visible bool) {
+	if s.visible.Swap(visible) == visible {
+		return
+	}
+	select {
+	case s.visibilityChanged <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) run() {
+	defer close(s.done)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	check := time.NewTicker(checkInterval)
+	defer check.Stop()
+	lastCheck := time.Now()
+	lastNetwork := networkSignature()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-timer.C:
+		case <-s.trigger:
+		case <-s.visibilityChanged:
+			if !s.visible.Load() || time.Since(s.lastSuccess) < s.interval {
+				timer.Reset(s.nextDelay())
+				continue
+			}
+		case now := <-check.C:
+			// The monotonic clock stops while the machine is asleep, but the wall clock does not.
+			woke := now.Round(0).Sub(lastCheck.Round(0))-now.Sub(lastCheck) > checkInterval
+			lastCheck = now
+			network := networkSignature()
+			reconnected := network != lastNetwork && network != ""
+			lastNetwork = network
+			if woke {
+				log.Println("Resumed from sleep. Refreshing.")
+			} else if reconnected {
+				log.Println("Network changed. Refreshing.")
+			} else {
+				continue
+			}
+		}
+		s.runRefresh()
+		timer.Reset(s.nextDelay())
+	}
+}
+
+func (s *Scheduler) runRefresh() {
+	s.lastAttempt = time.Now()
+	if err := s.refresh(); err != nil {
+		s.errorCount++
+		log.Println("ERROR: ", err)
+		return
+	}
+	s.errorCount = 0
+	s.lastSuccess = time.Now()
+}
+
+// nextDelay returns the time until the next refresh.
+func (s *Scheduler) nextDelay() time.Duration {
+	interval := s.interval
+	if !s.visible.Load() {
+		interval *= hiddenFactor
+	}
+	if s.errorCount > 0 {
+		// Back off exponentially from the current interval.
+		d := interval << min(s.errorCount-1, 10)
+		interval = min(d, max(s.interval*maxBackoff, interval))
+	}
+	return max(interval-time.Since(s.lastAttempt), 0)
+}
+
+// networkSignature returns the addresses of all active network interfaces except loopback.
+func networkSignature() string {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return ""
+	}
+	var addrs []string
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		xx, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, a := range xx {
+			addrs = append(addrs, a.String())
+		}
+	}
+	slices.Sort(addrs)
+	return strings.Join(addrs, ",")
+}
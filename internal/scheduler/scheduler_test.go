@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNextDelay(t *testing.T) {
+	const interval = 10 * time.Minute
+	cases := []struct {
+		name       string
+		visible    bool
+		errorCount int
+		want       time.Duration
+	}{
+		{"visible", true, 0, interval},
+		{"hidden", false, 0, interval * hiddenFactor},
+		{"first error", true, 1, interval},
+		{"second error", true, 2, 2 * interval},
+		{"third error", true, 3, 4 * interval},
+		{"capped", true, 4, maxBackoff * interval},
+		{"capped after many errors", true, 100, maxBackoff * interval},
+		{"hidden first error", false, 1, interval * hiddenFactor},
+		{"hidden capped", false, 5, maxBackoff * interval},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := New(func() error { return nil }, interval)
+			s.SetVisible(tc.visible)
+			s.errorCount = tc.errorCount
+			s.lastAttempt = time.Now()
+			got := s.nextDelay()
+			if got > tc.want || got < tc.want-time.Second {
+				t.Errorf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+	t.Run("since last attempt", func(t *testing.T) {
+		s := New(func() error { return nil }, interval)
+		s.lastAttempt = time.Now().Add(-interval / 2)
+		if got := s.nextDelay(); got > interval/2 || got < interval/2-time.Second {
+			t.Errorf("got %s, want %s", got, interval/2)
+		}
+		s.lastAttempt = time.Now().Add(-2 * interval)
+		if got := s.nextDelay(); got != 0 {
+			t.Errorf("got %s, want 0", got)
+		}
+	})
+}
+
+func TestRunRefreshResetsBackoff(t *testing.T) {
+	const interval = 10 * time.Minute
+	var err error
+	s := New(func() error { return err }, interval)
+	err = errors.New("failed")
+	for range 3 {
+		s.runRefresh()
+	}
+	if got := s.nextDelay(); got < 4*interval-time.Second {
+		t.Errorf("after errors: got %s, want %s", got, 4*interval)
+	}
+	err = nil
+	s.runRefresh()
+	if s.errorCount != 0 {
+		t.Errorf("got error count %d, want 0", s.errorCount)
+	}
+	if got := s.nextDelay(); got > interval || got < interval-time.Second {
+		t.Errorf("after success: got %s, want %s", got, interval)
+	}
+}
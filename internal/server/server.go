@@ -0,0 +1,96 @@
+// Package server provides a local HTTP API for the cached weather data.
+package server
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/ErikKalkoken/weatherapp/internal/ical"
+	"github.com/ErikKalkoken/weatherapp/internal/location"
+	"github.com/ErikKalkoken/weatherapp/internal/weather"
+)
+
+// Server serves weather data as JSON from the in-memory cache of a weather service.
+// It never calls upstream APIs itself.
+type Server struct {
+	mux     *http.ServeMux
+	service *weather.Service
+}
+
+func New(service *weather.Service) *Server {
+	s := &Server{
+		mux:     http.NewServeMux(),
+		service: service,
+	}
+	s.handle("/current", func(x weather.Snapshot) any {
+		return x.Forecast.Current
+	})
+	s.handle("/hourly", func(x weather.Snapshot) any {
+		return x.Forecast.Hourly
+	})
+	s.handle("/daily", func(x weather.Snapshot) any {
+		return x.Forecast.Daily
+	})
+	s.handle("/alerts", func(x weather.Snapshot) any {
+		return x.Alerts
+	})
+	s.handle("/warnings", func(x weather.Snapshot) any {
+		return x.Warnings
+	})
+	s.handle("/locations", func(x weather.Snapshot) any {
+		return []location.Location{x.Location}
+	})
+	s.mux.HandleFunc("GET /forecast.ics", func(w http.ResponseWriter, r *http.Request) {
+		x, ok := s.service.Snapshot()
+		if !ok {
+			http.Error(w, "no data yet", http.StatusServiceUnavailable)
+			return
+		}
+		var b bytes.Buffer
+		if err := ical.Encode(&b, x.Location, x.Forecast.Daily, x.UpdatedAt); err != nil {
+			log.Printf("ERROR: encoding calendar: %s", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		serveContent(w, r, "text/calendar; charset=utf-8", b.Bytes(), x)
+	})
+	return s
+}
+
+// Handle registers an additional handler for the given pattern.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handle registers a JSON endpoint which returns the data selected from the latest snapshot.
+func (s *Server) handle(pattern string, selector func(weather.Snapshot) any) {
+	s.mux.HandleFunc("GET "+pattern, func(w http.ResponseWriter, r *http.Request) {
+		x, ok := s.service.Snapshot()
+		if !ok {
+			http.Error(w, "no data yet", http.StatusServiceUnavailable)
+			return
+		}
+		data, err := json.Marshal(selector(x))
+		if err != nil {
+			log.Printf("ERROR: encoding %s: %s", pattern, err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		serveContent(w, r, "application/json", data, x)
+	})
+}
+
+// serveContent serves data from a snapshot with cache validators.
+func serveContent(w http.ResponseWriter, r *http.Request, contentType string, data []byte, x weather.Snapshot) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("ETag", fmt.Sprintf(`"%x"`, sha1.Sum(data)))
+	http.ServeContent(w, r, "", x.UpdatedAt, bytes.NewReader(data))
+}
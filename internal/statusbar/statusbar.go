@@ -0,0 +1,80 @@
+// Package statusbar formats the current weather for status bars like waybar, i3bar and polybar.
+package statusbar
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	"github.com/ErikKalkoken/weatherapp/internal/weather"
+	"github.com/ErikKalkoken/weatherapp/internal/weathercode"
+)
+
+const tooltipHours = 6
+
+// Text returns a single line with the current weather.
+// The line starts with a warning sign when there are alerts.
+func Text(x weather.Snapshot) string {
+	c := x.Forecast.Current
+	s := fmt.Sprintf("%.0f° %s %d%%", c.Temperature2m, cases.Title(language.English).String(c.Description()), c.PrecipitationProbability)
+	if len(x.Alerts) > 0 || len(x.Warnings) > 0 {
+		s = "⚠ " + s
+	}
+	return s
+}
+
+// Waybar returns the current weather in the JSON format for custom waybar modules.
+func Waybar(x weather.Snapshot) ([]byte, error) {
+	c := x.Forecast.Current
+	daytime := "day"
+	if !c.IsDay {
+		daytime = "night"
+	}
+	info, _ := weathercode.Lookup(c.WeatherCode)
+	class := []string{info.Category.String(), daytime}
+	if len(x.Alerts) > 0 {
+		class = append(class, "alert-"+x.Alerts[0].Severity.String())
+	}
+	if len(x.Warnings) > 0 {
+		class = append(class, "warning")
+	}
+	v := struct {
+		Text    string   `json:"text"`
+		Tooltip string   `json:"tooltip"`
+		Class   []string `json:"class"`
+	}{
+		Text:    Text(x),
+		Tooltip: tooltip(x),
+		Class:   class,
+	}
+	return json.Marshal(v)
+}
+
+func tooltip(x weather.Snapshot) string {
+	c := x.Forecast.Current
+	lines := []string{
+		fmt.Sprintf("%s / %s", x.Location.City, x.Location.Country),
+	}
+	for _, w := range x.Warnings {
+		lines = append(lines, fmt.Sprintf("⚠ %s (%s)", cmp.Or(w.Headline, w.Event), w.Severity))
+	}
+	for _, a := range x.Alerts {
+		lines = append(lines, "⚠ "+a.Message)
+	}
+	lines = append(lines, fmt.Sprintf("%s, %.0f°, %d%% precipitation", cases.Title(language.English).String(c.Description()), c.Temperature2m, c.PrecipitationProbability))
+	for i, h := range x.Forecast.Hourly {
+		if i == tooltipHours {
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%02d: %.0f° %d%% %s", h.Time.Hour(), h.Temperature2m, h.PrecipitationProbability, h.Description()))
+	}
+	if len(x.Forecast.Daily) > 0 {
+		d := x.Forecast.Daily[0]
+		lines = append(lines, fmt.Sprintf("Today: %.0f° / %.0f°", d.Temperature2mMin, d.Temperature2mMax))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,275 @@
+// Package store keeps a history of forecasts and observed weather in a local database.
+//
+// The history allows to track the accuracy of forecasts by comparing
+// what was forecasted for a time with what was observed at that time.
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/ErikKalkoken/weatherapp/internal/location"
+	"github.com/ErikKalkoken/weatherapp/internal/weather"
+)
+
+const (
+	// Observations are only recorded when they are this close to a full hour.
+	maxObservationOffset = 15 * time.Minute
+	// Records older than this are removed.
+	retention = 90 * 24 * time.Hour
+	// Layout for times in keys. Keys with this layout sort chronologically.
+	keyTimeLayout = "2006-01-02T15:04Z"
+)
+
+var (
+	bucketForecasts    = []byte("forecasts")
+	bucketObservations = []byte("observations")
+)
+
+// record is the weather forecasted or observed for a location at a time.
+// Temperatures are always stored in °C, so that records stay comparable when the user changes the unit.
+type record struct {
+	Temperature float64 `json:"temperature"`
+	Unit        string  `json:"unit"` // unit of the temperature; empty for records of older versions, which are ignored
+	WeatherCode int     `json:"weather_code"`
+}
+
+const celsius = "°C"
+
+// makeRecord returns a record for a temperature in a unit, e.g. "°F", and a weather code.
+func makeRecord(temperature float64, unit string, code int) record {
+	if unit == "°F" {
+		temperature = (temperature - 32) * 5 / 9
+	}
+	return record{Temperature: temperature, Unit: celsius, WeatherCode: code}
+}
+
+// LeadTimeError is the forecast error for a range of lead times.
+type LeadTimeError struct {
+	From  time.Duration // lead times from (inclusive)
+	To    time.Duration // lead times to (exclusive)
+	MAE   float64       // mean absolute error of the temperature
+	Count int           // number of compared forecasts
+}
+
+// Label returns a short description of the lead time range, e.g. "12-24h".
+func (x LeadTimeError) Label() string {
+	return fmt.Sprintf("%.0f-%.0fh", x.From.Hours(), x.To.Hours())
+}
+
+// Lead time ranges for accuracy statistics.
+var leadTimeBounds = []time.Duration{
+	0,
+	3 * time.Hour,
+	6 * time.Hour,
+	12 * time.Hour,
+	24 * time.Hour,
+	48 * time.Hour,
+	72 * time.Hour,
+	120 * time.Hour,
+	240 * time.Hour,
+	384 * time.Hour,
+}
+
+// Store is a history of forecasts and observations.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens the store at path and creates it when it does not exist.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("opening history store %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{bucketForecasts, bucketObservations} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	s := &Store{db: db}
+	return s, nil
+}
+
+// Close closes the store.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Save records the forecasts and the observed current weather from a snapshot.
+// Forecasts are recorded once per hour of issue, later forecasts issued in the same hour replace earlier ones.
+func (s *Store) Save(x weather.Snapshot) error {
+	loc := locationKey(x.Location)
+	unit := x.Forecast.Units.Temperature
+	issued := x.UpdatedAt.UTC().Truncate(time.Hour)
+	return s.db.Update(func(tx *bolt.Tx) error {
+		forecasts := tx.Bucket(bucketForecasts)
+		for _, h := range x.Forecast.Hourly {
+			data, err := json.Marshal(makeRecord(h.Temperature2m, unit, h.WeatherCode))
+			if err != nil {
+				return err
+			}
+			key := makeKey(loc, h.Time, issued)
+			if err := forecasts.Put(key, data); err != nil {
+				return err
+			}
+		}
+		c := x.Forecast.Current
+		t := c.Time.UTC().Round(time.Hour)
+		if d := c.Time.Sub(t); d.Abs() <= maxObservationOffset {
+			data, err := json.Marshal(makeRecord(c.Temperature2m, unit, c.WeatherCode))
+			if err != nil {
+				return err
+			}
+			if err := tx.Bucket(bucketObservations).Put(makeKey(loc, t), data); err != nil {
+				return err
+			}
+		}
+		return prune(tx, loc, time.Now().Add(-retention))
+	})
+}
+
+// Observer returns an observer for saving each updated snapshot of a weather service.
+func (s *Store) Observer() weather.Observer {
+	return weather.SnapshotFunc(func(x weather.Snapshot) {
+		if err := s.Save(x); err != nil {
+			log.Printf("ERROR: saving to history: %s", err)
+		}
+	})
+}
+
+// Accuracy returns the mean absolute error of the forecasted temperature for a location by lead time.
+// The errors are returned in a temperature unit, e.g. "°F". Lead time ranges without data are omitted.
+func (s *Store) Accuracy(l location.Location, unit string) ([]LeadTimeError, error) {
+	loc := locationKey(l)
+	sums := make([]float64, len(leadTimeBounds)-1)
+	counts := make([]int, len(leadTimeBounds)-1)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		forecasts := tx.Bucket(bucketForecasts).Cursor()
+		prefix := []byte(loc + "|")
+		c := tx.Bucket(bucketObservations).Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			var obs record
+			if err := json.Unmarshal(v, &obs); err != nil {
+				return err
+			}
+			if obs.Unit != celsius {
+				continue
+			}
+			fp := append(bytes.Clone(k), '|')
+			for fk, fv := forecasts.Seek(fp); fk != nil && bytes.HasPrefix(fk, fp); fk, fv = forecasts.Next() {
+				valid, issued, err := parseForecastKey(fk)
+				if err != nil {
+					return err
+				}
+				var f record
+				if err := json.Unmarshal(fv, &f); err != nil {
+					return err
+				}
+				if f.Unit != celsius {
+					continue
+				}
+				i := leadTimeIndex(valid.Sub(issued))
+				if i < 0 {
+					continue
+				}
+				sums[i] += math.Abs(f.Temperature - obs.Temperature)
+				counts[i]++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	scale := 1.0
+	if unit == "°F" {
+		scale = 1.8
+	}
+	result := make([]LeadTimeError, 0)
+	for i, n := range counts {
+		if n == 0 {
+			continue
+		}
+		result = append(result, LeadTimeError{
+			From:  leadTimeBounds[i],
+			To:    leadTimeBounds[i+1],
+			MAE:   sums[i] / float64(n) * scale,
+			Count: n,
+		})
+	}
+	return result, nil
+}
+
+func leadTimeIndex(d time.Duration) int {
+	for i := range len(leadTimeBounds) - 1 {
+		if d >= leadTimeBounds[i] && d < leadTimeBounds[i+1] {
+			return i
+		}
+	}
+	return -1
+}
+
+// prune removes all records of a location which are valid before t.
+func prune(tx *bolt.Tx, loc string, t time.Time) error {
+	prefix := []byte(loc + "|")
+	end := makeKey(loc, t)
+	for _, name := range [][]byte{bucketForecasts, bucketObservations} {
+		b := tx.Bucket(name)
+		var expired [][]byte
+		c := b.Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix) && bytes.Compare(k, end) < 0; k, _ = c.Next() {
+			expired = append(expired, bytes.Clone(k))
+		}
+		for _, k := range expired {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// locationKey returns a key for a location, which is stable for small changes of the coordinates.
+func locationKey(l location.Location) string {
+	return fmt.Sprintf("%.2f,%.2f", l.Latitude, l.Longitude)
+}
+
+// makeKey returns a key for a location and one or more times.
+func makeKey(loc string, times ...time.Time) []byte {
+	parts := []string{loc}
+	for _, t := range times {
+		parts = append(parts, t.UTC().Format(keyTimeLayout))
+	}
+	return []byte(strings.Join(parts, "|"))
+}
+
+func parseForecastKey(k []byte) (valid time.Time, issued time.Time, err error) {
+	parts := strings.Split(string(k), "|")
+	if len(parts) != 3 {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid key: %s", k)
+	}
+	valid, err = time.Parse(keyTimeLayout, parts[1])
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	issued, err = time.Parse(keyTimeLayout, parts[2])
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return valid, issued, nil
+}
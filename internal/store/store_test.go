@@ -0,0 +1,218 @@
+package store
+
+import (
+	"encoding/json"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/ErikKalkoken/weatherapp/internal/forecast"
+	"github.com/ErikKalkoken/weatherapp/internal/location"
+	"github.com/ErikKalkoken/weatherapp/internal/weather"
+)
+
+var berlin = location.Location{City: "Berlin", Latitude: 52.52, Longitude: 13.41}
+
+func openStore(t *testing.T) *Store {
+	s, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// makeSnapshot returns a snapshot updated at a time with the current weather at that time
+// and forecasts for the given times.
+func makeSnapshot(updated time.Time, unit string, current float64, forecasts map[time.Time]float64) weather.Snapshot {
+	x := weather.Snapshot{
+		Location:  berlin,
+		UpdatedAt: updated,
+		Forecast: forecast.Result{
+			Current: forecast.ForecastHour{Time: updated, Temperature2m: current, WeatherCode: 3},
+			Units:   forecast.Units{Temperature: unit},
+		},
+	}
+	for t, v := range forecasts {
+		x.Forecast.Hourly = append(x.Forecast.Hourly, forecast.ForecastHour{Time: t, Temperature2m: v, WeatherCode: 61})
+	}
+	return x
+}
+
+// records returns the records of a bucket by key.
+func records(t *testing.T, s *Store, bucket []byte) map[string]record {
+	result := make(map[string]record)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).ForEach(func(k, v []byte) error {
+			var r record
+			if err := json.Unmarshal(v, &r); err != nil {
+				return err
+			}
+			result[string(k)] = r
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestSave(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Hour)
+	t.Run("records forecasts and observation", func(t *testing.T) {
+		s := openStore(t)
+		x := makeSnapshot(now.Add(5*time.Minute), "°C", 10, map[time.Time]float64{
+			now.Add(time.Hour):     11,
+			now.Add(2 * time.Hour): 12,
+		})
+		if err := s.Save(x); err != nil {
+			t.Fatal(err)
+		}
+		forecasts := records(t, s, bucketForecasts)
+		if len(forecasts) != 2 {
+			t.Fatalf("got %d forecasts, want 2", len(forecasts))
+		}
+		want := record{Temperature: 11, Unit: "°C", WeatherCode: 61}
+		if got := forecasts[string(makeKey("52.52,13.41", now.Add(time.Hour), now))]; got != want {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+		observations := records(t, s, bucketObservations)
+		want = record{Temperature: 10, Unit: "°C", WeatherCode: 3}
+		if got := observations[string(makeKey("52.52,13.41", now))]; got != want {
+			t.Errorf("got observations %+v, want %+v", observations, want)
+		}
+	})
+	t.Run("no observation far from full hour", func(t *testing.T) {
+		s := openStore(t)
+		if err := s.Save(makeSnapshot(now.Add(30*time.Minute), "°C", 10, nil)); err != nil {
+			t.Fatal(err)
+		}
+		if got := len(records(t, s, bucketObservations)); got != 0 {
+			t.Errorf("got %d observations, want 0", got)
+		}
+	})
+	t.Run("later forecasts of the same hour replace earlier ones", func(t *testing.T) {
+		s := openStore(t)
+		valid := now.Add(3 * time.Hour)
+		if err := s.Save(makeSnapshot(now.Add(5*time.Minute), "°C", 10, map[time.Time]float64{valid: 11})); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Save(makeSnapshot(now.Add(40*time.Minute), "°C", 10, map[time.Time]float64{valid: 13})); err != nil {
+			t.Fatal(err)
+		}
+		forecasts := records(t, s, bucketForecasts)
+		if len(forecasts) != 1 {
+			t.Fatalf("got %d forecasts, want 1", len(forecasts))
+		}
+		for _, r := range forecasts {
+			if r.Temperature != 13 {
+				t.Errorf("got %v, want 13", r.Temperature)
+			}
+		}
+	})
+	t.Run("temperatures are stored in celsius", func(t *testing.T) {
+		s := openStore(t)
+		if err := s.Save(makeSnapshot(now, "°F", 50, map[time.Time]float64{now.Add(time.Hour): 212})); err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range records(t, s, bucketForecasts) {
+			if r.Temperature != 100 || r.Unit != "°C" {
+				t.Errorf("got %+v", r)
+			}
+		}
+		for _, r := range records(t, s, bucketObservations) {
+			if r.Temperature != 10 || r.Unit != "°C" {
+				t.Errorf("got %+v", r)
+			}
+		}
+	})
+	t.Run("removes records older than 90 days", func(t *testing.T) {
+		s := openStore(t)
+		old := now.Add(-retention - 24*time.Hour)
+		if err := s.Save(makeSnapshot(old, "°C", 10, map[time.Time]float64{old.Add(time.Hour): 11, now.Add(time.Hour): 12})); err != nil {
+			t.Fatal(err)
+		}
+		forecasts := records(t, s, bucketForecasts)
+		if len(forecasts) != 1 {
+			t.Errorf("got %d forecasts, want 1", len(forecasts))
+		}
+		if got := len(records(t, s, bucketObservations)); got != 0 {
+			t.Errorf("got %d observations, want 0", got)
+		}
+	})
+}
+
+func TestAccuracy(t *testing.T) {
+	valid := time.Now().UTC().Truncate(time.Hour).Add(-time.Hour)
+	s := openStore(t)
+	snapshots := []weather.Snapshot{
+		makeSnapshot(valid.Add(-30*time.Hour), "°C", 0, map[time.Time]float64{valid: 13}),
+		makeSnapshot(valid.Add(-26*time.Hour), "°C", 0, map[time.Time]float64{valid: 7}),
+		// A forecast in another unit after the user switched the temperature unit.
+		makeSnapshot(valid.Add(-2*time.Hour), "°F", 0, map[time.Time]float64{valid: 51.8}), // 11°C
+		makeSnapshot(valid, "°C", 10, nil),
+	}
+	for _, x := range snapshots {
+		if err := s.Save(x); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A record of an older version without unit is ignored.
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		data, _ := json.Marshal(record{Temperature: 60})
+		return tx.Bucket(bucketForecasts).Put(makeKey("52.52,13.41", valid, valid.Add(-5*time.Hour)), data)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		unit string
+		want []LeadTimeError
+	}{
+		{"°C", []LeadTimeError{
+			{From: 0, To: 3 * time.Hour, MAE: 1, Count: 1},
+			{From: 24 * time.Hour, To: 48 * time.Hour, MAE: 3, Count: 2},
+		}},
+		{"°F", []LeadTimeError{
+			{From: 0, To: 3 * time.Hour, MAE: 1.8, Count: 1},
+			{From: 24 * time.Hour, To: 48 * time.Hour, MAE: 5.4, Count: 2},
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.unit, func(t *testing.T) {
+			got, err := s.Accuracy(berlin, tc.unit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %+v, want %+v", got, tc.want)
+			}
+			for i, w := range tc.want {
+				g := got[i]
+				if g.From != w.From || g.To != w.To || g.Count != w.Count || math.Abs(g.MAE-w.MAE) > 1e-9 {
+					t.Errorf("got %+v, want %+v", g, w)
+				}
+			}
+		})
+	}
+	t.Run("other location", func(t *testing.T) {
+		got, err := s.Accuracy(location.Location{Latitude: 48.14, Longitude: 11.58}, "°C")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 0 {
+			t.Errorf("got %+v", got)
+		}
+	})
+}
+
+func TestLeadTimeErrorLabel(t *testing.T) {
+	x := LeadTimeError{From: 12 * time.Hour, To: 24 * time.Hour}
+	if got := x.Label(); got != "12-24h" {
+		t.Errorf("got %q", got)
+	}
+}
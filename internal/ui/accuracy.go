@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showAccuracy shows the accuracy of past forecasts for the current location in a dialog.
+func (u *ui) showAccuracy() {
+	if u.history == nil {
+		dialog.ShowInformation("Forecast accuracy", "The forecast history is disabled.", u.window)
+		return
+	}
+	x, ok := u.service.Snapshot()
+	if !ok {
+		dialog.ShowInformation("Forecast accuracy", "No weather data yet.", u.window)
+		return
+	}
+	errs, err := u.history.Accuracy(x.Location, x.Forecast.Units.Temperature)
+	if err != nil {
+		dialog.ShowError(err, u.window)
+		return
+	}
+	if len(errs) == 0 {
+		dialog.ShowInformation(
+			"Forecast accuracy",
+			"Not enough history yet.\nForecasts can be compared once their time has come.",
+			u.window,
+		)
+		return
+	}
+	grid := container.NewGridWithColumns(3,
+		widget.NewLabelWithStyle("Lead time", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("Mean error", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("Samples", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+	)
+	for _, e := range errs {
+		grid.Add(widget.NewLabel(e.Label()))
+		grid.Add(widget.NewLabelWithStyle(fmt.Sprintf("±%.1f%s", e.MAE, x.Forecast.Units.Temperature), fyne.TextAlignTrailing, fyne.TextStyle{}))
+		grid.Add(widget.NewLabelWithStyle(fmt.Sprint(e.Count), fyne.TextAlignTrailing, fyne.TextStyle{}))
+	}
+	c := container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("Temperature forecasts for %s", x.Location.City)),
+		grid,
+	)
+	dialog.ShowCustom("Forecast accuracy", "Close", c, u.window)
+}
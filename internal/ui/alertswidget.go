@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/weatherapp/internal/alerts"
+)
+
+// AlertsWidget is a banner showing weather alerts. It is hidden when there are no alerts.
+type AlertsWidget struct {
+	widget.BaseWidget
+	box *fyne.Container
+}
+
+func NewAlertsWidget() *AlertsWidget {
+	w := &AlertsWidget{
+		box: container.NewVBox(),
+	}
+	w.ExtendBaseWidget(w)
+	w.Hide()
+	return w
+}
+
+func (w *AlertsWidget) Set(aa []alerts.Alert) {
+	w.box.RemoveAll()
+	for _, a := range aa {
+		l := widget.NewLabel(a.Message)
+		l.Wrapping = fyne.TextWrapWord
+		bg := canvas.NewRectangle(theme.Color(severityColor(a.Severity)))
+		bg.CornerRadius = theme.InputRadiusSize()
+		w.box.Add(container.NewStack(bg, l))
+	}
+	if len(aa) == 0 {
+		w.Hide()
+	} else {
+		w.Show()
+	}
+	w.Refresh()
+}
+
+func severityColor(s alerts.Severity) fyne.ThemeColorName {
+	switch s {
+	case alerts.Severe:
+		return theme.ColorNameError
+	case alerts.Warning:
+		return theme.ColorNameWarning
+	}
+	return theme.ColorNamePrimary
+}
+
+func (w *AlertsWidget) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(w.box)
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
@@ -17,6 +18,9 @@ type CurrentWeatherWidget struct {
 	city        *widget.Label
 	temperature *widget.RichText
 	description *widget.Label
+	comfort     *widget.Label
+	lastYear    *widget.Label
+	yesterday   *widget.Label
 }
 
 func NewCurrentWeatherWidget() *CurrentWeatherWidget {
@@ -24,7 +28,13 @@ func NewCurrentWeatherWidget() *CurrentWeatherWidget {
 		city:        widget.NewLabel(""),
 		temperature: widget.NewRichTextFromMarkdown(""),
 		description: widget.NewLabel(""),
+		comfort:     widget.NewLabel(""),
+		lastYear:    widget.NewLabel(""),
+		yesterday:   widget.NewLabel(""),
 	}
+	w.lastYear.Hide()
+	w.comfort.Alignment = fyne.TextAlignCenter
+	w.yesterday.Alignment = fyne.TextAlignCenter
 	w.ExtendBaseWidget(w)
 	return w
 }
@@ -40,17 +50,84 @@ func (w *CurrentWeatherWidget) Set(l location.Location, f forecast.ForecastHour)
 	w.city.SetText(city)
 	t := fmt.Sprintf("# %.0f°", f.Temperature2m)
 	w.temperature.ParseMarkdown(t)
-	m := weatherCodeMappings[f.WeatherCode]
 	x := cases.Title(language.English)
-	description := x.String(m.description)
+	description := x.String(f.Description())
 	w.description.SetText(description)
 }
 
+// SetComfort shows how the current weather feels.
+// The heat stress metrics are only shown when it is hot.
+func (w *CurrentWeatherWidget) SetComfort(f forecast.ForecastHour, units forecast.Units) {
+	m := f.Comfort(units)
+	text := fmt.Sprintf(
+		"Feels like %.0f° · Humidity %d%% · Dew point %.0f° (%s)",
+		m.FeelsLike, f.RelativeHumidity2m, m.DewPoint, m.DewPointCategory,
+	)
+	hot := 26.7
+	if units.Temperature == "°F" {
+		hot = 80
+	}
+	if f.Temperature2m >= hot {
+		text += fmt.Sprintf("\nHeat index %.0f° · Humidex %.0f · WBGT %.0f°", m.HeatIndex, m.Humidex, m.WBGT)
+	}
+	w.comfort.SetText(text)
+}
+
+// SetYesterday shows a comparison with this time yesterday and the recent precipitation.
+func (w *CurrentWeatherWidget) SetYesterday(r forecast.Result) {
+	var parts []string
+	if y, ok := r.Yesterday(); ok {
+		d := r.Current.Temperature2m - y.Temperature2m
+		switch {
+		case d >= 0.5:
+			parts = append(parts, fmt.Sprintf("%.0f° warmer than this time yesterday", d))
+		case d <= -0.5:
+			parts = append(parts, fmt.Sprintf("%.0f° colder than this time yesterday", -d))
+		default:
+			parts = append(parts, "Same temperature as this time yesterday")
+		}
+	}
+	if len(r.Past) > 0 {
+		if p := r.PrecipitationLast24h(); p > 0 {
+			parts = append(parts, fmt.Sprintf("%.1f %s precipitation in the last 24h", p, r.Units.Precipitation))
+		} else {
+			parts = append(parts, "No precipitation in the last 24h")
+		}
+	}
+	w.yesterday.SetText(strings.Join(parts, "\n"))
+}
+
+// SetLastYear shows a comparison of today with the same day last year.
+func (w *CurrentWeatherWidget) SetLastYear(today, lastYear forecast.ForecastDay) {
+	var diff string
+	d := today.Temperature2mMax - lastYear.Temperature2mMax
+	switch {
+	case d >= 0.5:
+		diff = fmt.Sprintf("%.0f° warmer", d)
+	case d <= -0.5:
+		diff = fmt.Sprintf("%.0f° colder", -d)
+	default:
+		diff = "about the same"
+	}
+	w.lastYear.SetText(fmt.Sprintf(
+		"vs. last year: %.0f° / %.0f° (%s)", lastYear.Temperature2mMin, lastYear.Temperature2mMax, diff,
+	))
+	w.lastYear.Show()
+}
+
+// HideLastYear hides the comparison with last year.
+func (w *CurrentWeatherWidget) HideLastYear() {
+	w.lastYear.Hide()
+}
+
 func (w *CurrentWeatherWidget) CreateRenderer() fyne.WidgetRenderer {
 	c := container.NewVBox(
 		container.NewCenter(w.city),
 		container.NewCenter(w.temperature),
 		container.NewCenter(w.description),
+		container.NewCenter(w.comfort),
+		container.NewCenter(w.yesterday),
+		container.NewCenter(w.lastYear),
 	)
 	return widget.NewSimpleRenderer(c)
 }
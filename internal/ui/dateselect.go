@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// dateSelect lets the user select a date between a first and a last date with one select each for year, month and day.
+type dateSelect struct {
+	widget.BaseWidget
+
+	// OnChanged is called when the user selects another date.
+	OnChanged func(time.Time)
+
+	first, last time.Time
+	date        time.Time
+	day         *widget.Select
+	month       *widget.Select
+	year        *widget.Select
+	updating    bool // whether the selects are being updated by the widget itself
+}
+
+func newDateSelect(first, last time.Time) *dateSelect {
+	w := &dateSelect{
+		first: dateOnly(first),
+		last:  dateOnly(last),
+	}
+	var years []string
+	for y := w.last.Year(); y >= w.first.Year(); y-- {
+		years = append(years, strconv.Itoa(y))
+	}
+	var months []string
+	for m := time.January; m <= time.December; m++ {
+		months = append(months, m.String()[:3])
+	}
+	w.day = widget.NewSelect(nil, func(string) { w.changed() })
+	w.month = widget.NewSelect(months, func(string) { w.changed() })
+	w.year = widget.NewSelect(years, func(string) { w.changed() })
+	w.ExtendBaseWidget(w)
+	w.SetDate(w.last)
+	return w
+}
+
+// Date returns the selected date.
+func (w *dateSelect) Date() time.Time {
+	return w.date
+}
+
+// SetDate selects a date. Dates outside of the range are moved to the first or the last date.
+func (w *dateSelect) SetDate(t time.Time) {
+	t = dateOnly(t)
+	if t.Before(w.first) {
+		t = w.first
+	}
+	if t.After(w.last) {
+		t = w.last
+	}
+	w.date = t
+	w.updating = true
+	defer func() { w.updating = false }()
+	var days []string
+	for d := 1; d <= daysIn(t.Year(), t.Month()); d++ {
+		days = append(days, strconv.Itoa(d))
+	}
+	w.day.Options = days
+	w.day.SetSelectedIndex(t.Day() - 1)
+	w.month.SetSelectedIndex(int(t.Month()) - 1)
+	w.year.SetSelected(strconv.Itoa(t.Year()))
+}
+
+// changed updates the date after the user changed one of the selects.
+func (w *dateSelect) changed() {
+	if w.updating {
+		return
+	}
+	year, err := strconv.Atoi(w.year.Selected)
+	if err != nil {
+		return
+	}
+	month := time.Month(w.month.SelectedIndex() + 1)
+	day := min(w.day.SelectedIndex()+1, daysIn(year, month))
+	w.SetDate(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+	if w.OnChanged != nil {
+		w.OnChanged(w.date)
+	}
+}
+
+func (w *dateSelect) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewHBox(w.day, w.month, w.year))
+}
+
+// dateOnly returns the date of a time as midnight UTC.
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// daysIn returns the number of days of a month.
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
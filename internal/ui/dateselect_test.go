@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestDateSelect(t *testing.T) {
+	test.NewApp()
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	first, last := date(2020, 1, 7), date(2024, 11, 20)
+	t.Run("starts with last date", func(t *testing.T) {
+		w := newDateSelect(first, last)
+		if got := w.Date(); !got.Equal(last) {
+			t.Errorf("got %v, want %v", got, last)
+		}
+	})
+	t.Run("clamps dates", func(t *testing.T) {
+		w := newDateSelect(first, last)
+		w.SetDate(date(2025, 1, 1))
+		if got := w.Date(); !got.Equal(last) {
+			t.Errorf("got %v, want %v", got, last)
+		}
+		w.SetDate(date(2019, 1, 1))
+		if got := w.Date(); !got.Equal(first) {
+			t.Errorf("got %v, want %v", got, first)
+		}
+	})
+	t.Run("selecting a shorter month moves the day", func(t *testing.T) {
+		w := newDateSelect(first, last)
+		w.SetDate(date(2024, 1, 31))
+		var changed time.Time
+		w.OnChanged = func(t time.Time) { changed = t }
+		w.month.SetSelected("Feb")
+		want := date(2024, 2, 29)
+		if got := w.Date(); !got.Equal(want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		if !changed.Equal(want) {
+			t.Errorf("OnChanged: got %v, want %v", changed, want)
+		}
+		if got := len(w.day.Options); got != 29 {
+			t.Errorf("got %d days, want 29", got)
+		}
+	})
+	t.Run("setting a date does not call OnChanged", func(t *testing.T) {
+		w := newDateSelect(first, last)
+		w.OnChanged = func(time.Time) { t.Error("OnChanged called") }
+		w.SetDate(date(2022, 6, 15))
+	})
+}
@@ -5,7 +5,9 @@ import (
 	"time"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
 	"github.com/ErikKalkoken/fyne-kx/layout"
@@ -15,28 +17,34 @@ import (
 
 type DayForecastWidget struct {
 	widget.BaseWidget
+	amount         *widget.Label
 	day            *widget.Label
 	precipitation  *widget.Label
+	temperatures   *TemperatureRangeWidget
 	symbol         *widget.Icon
-	temperatureMax *widget.Label
-	temperatureMin *widget.Label
+	temperatureMax *canvas.Text
+	temperatureMin *canvas.Text
 }
 
 func NewDayForecastWidget() *DayForecastWidget {
 	p := widget.NewLabel("")
 	p.Importance = widget.HighImportance
 	w := &DayForecastWidget{
+		amount:         widget.NewLabel(""),
 		day:            widget.NewLabel(""),
 		precipitation:  p,
 		symbol:         widget.NewIcon(resourceBlankSvg),
-		temperatureMax: widget.NewLabel(""),
-		temperatureMin: widget.NewLabel(""),
+		temperatures:   NewTemperatureRangeWidget(),
+		temperatureMax: canvas.NewText("", theme.Color(theme.ColorNameForeground)),
+		temperatureMin: canvas.NewText("", theme.Color(theme.ColorNameForeground)),
 	}
 	w.ExtendBaseWidget(w)
 	return w
 }
 
-func (w *DayForecastWidget) Set(f forecast.ForecastDay, icon fyne.Resource) {
+// Set shows the forecast for a day.
+// The range of its temperatures is shown relative to the lowest and highest temperature of all shown days.
+func (w *DayForecastWidget) Set(f forecast.ForecastDay, units forecast.Units, icon fyne.Resource, low, high float64) {
 	var text string
 	if f.Time.Day() == time.Now().UTC().Day() {
 		text = "Today"
@@ -44,21 +52,50 @@ func (w *DayForecastWidget) Set(f forecast.ForecastDay, icon fyne.Resource) {
 		text = f.Time.Weekday().String()
 	}
 	w.day.SetText(text)
-	w.temperatureMin.SetText(fmt.Sprintf("%.0f°", f.Temperature2mMin))
-	w.temperatureMax.SetText(fmt.Sprintf("%.0f°", f.Temperature2mMax))
+	setTemperature(w.temperatureMin, f.Temperature2mMin, units)
+	setTemperature(w.temperatureMax, f.Temperature2mMax, units)
+	w.temperatures.Set(f.Temperature2mMin, f.Temperature2mMax, low, high, units)
 	w.precipitation.SetText(fmt.Sprintf("%d%%", f.PrecipitationProbabilityMean))
+	w.amount.SetText(formatAmount(f.PrecipitationSum, f.RainSum+f.ShowersSum, f.SnowfallSum, units))
 	w.symbol.SetResource(icon)
 }
 
+// SetCurrent shows the current temperature t in the range of temperatures.
+func (w *DayForecastWidget) SetCurrent(t, low, high float64) {
+	w.temperatures.SetCurrent(t, low, high)
+}
+
 func (w *DayForecastWidget) CreateRenderer() fyne.WidgetRenderer {
-	l := layout.NewColumns(100, 50, 50, 50, 50)
+	l := layout.NewColumns(100, 50, 50, 60, 50, 100, 50)
 	c := container.New(
 		l,
 		w.day,
 		container.NewCenter(w.symbol),
 		container.NewCenter(w.precipitation),
+		container.NewCenter(w.amount),
 		container.NewCenter(w.temperatureMin),
+		w.temperatures,
 		container.NewCenter(w.temperatureMax),
 	)
 	return widget.NewSimpleRenderer(c)
 }
+
+// setTemperature shows a temperature in the color of the temperature scale.
+func setTemperature(x *canvas.Text, t float64, units forecast.Units) {
+	x.Text = fmt.Sprintf("%.0f°", t)
+	x.Color = temperatureColor(t, units.Temperature)
+	x.Refresh()
+}
+
+// formatAmount returns the amount of precipitation, e.g. "1.2 mm".
+// It returns the amount of snow instead, when there is only snowfall.
+// It returns an empty string when there is no precipitation.
+func formatAmount(precipitation, rain, snowfall float64, units forecast.Units) string {
+	if snowfall > 0 && rain == 0 {
+		return fmt.Sprintf("%.1f %s", snowfall, units.Snowfall)
+	}
+	if precipitation == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.1f %s", precipitation, units.Precipitation)
+}
@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ErikKalkoken/weatherapp/internal/apierror"
+)
+
+// errorMessage returns a message for users explaining an error.
+func errorMessage(err error) string {
+	var httpErr *apierror.HTTPStatusError
+	var apiErr *apierror.APIError
+	switch {
+	case errors.Is(err, apierror.ErrNetwork):
+		return "Can not reach the weather service. Please check your internet connection."
+	case errors.As(err, &httpErr):
+		switch {
+		case httpErr.StatusCode == http.StatusTooManyRequests:
+			return "Too many requests to the weather service. Please try again later."
+		case httpErr.StatusCode >= 500:
+			return fmt.Sprintf("The weather service is currently unavailable (%s).", httpErr.Status)
+		}
+		return fmt.Sprintf("The request to the weather service failed (%s).", httpErr.Status)
+	case errors.As(err, &apiErr):
+		return fmt.Sprintf("The weather service reported an error: %s", apiErr.Reason)
+	case errors.Is(err, apierror.ErrNotFound):
+		return "The location could not be found. Please check the configured city."
+	case errors.Is(err, apierror.ErrMissingField), errors.Is(err, apierror.ErrMalformedValue):
+		return "Received unexpected data from the weather service."
+	}
+	return fmt.Sprintf("Failed to refresh: %s", err)
+}
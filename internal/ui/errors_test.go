@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ErikKalkoken/weatherapp/internal/apierror"
+)
+
+func TestErrorMessage(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			"network",
+			&apierror.NetworkError{API: "test", Err: errors.New("connection refused")},
+			"Can not reach the weather service. Please check your internet connection.",
+		},
+		{
+			"too many requests",
+			&apierror.HTTPStatusError{API: "test", StatusCode: 429, Status: "429 Too Many Requests"},
+			"Too many requests to the weather service. Please try again later.",
+		},
+		{
+			"server error",
+			&apierror.HTTPStatusError{API: "test", StatusCode: 503, Status: "503 Service Unavailable"},
+			"The weather service is currently unavailable (503 Service Unavailable).",
+		},
+		{
+			"client error",
+			&apierror.HTTPStatusError{API: "test", StatusCode: 404, Status: "404 Not Found"},
+			"The request to the weather service failed (404 Not Found).",
+		},
+		{
+			"API error",
+			&apierror.APIError{API: "test", Reason: "Invalid latitude"},
+			"The weather service reported an error: Invalid latitude",
+		},
+		{
+			"not found",
+			fmt.Errorf("location Nowhere: %w", apierror.ErrNotFound),
+			"The location could not be found. Please check the configured city.",
+		},
+		{
+			"missing field",
+			&apierror.MissingFieldError{Field: "hourly.weather_code"},
+			"Received unexpected data from the weather service.",
+		},
+		{
+			"malformed value",
+			&apierror.MalformedValueError{Field: "response", Err: errors.New("invalid character")},
+			"Received unexpected data from the weather service.",
+		},
+		{
+			"wrapped",
+			fmt.Errorf("site Berlin: %w", &apierror.NetworkError{API: "test", Err: errors.New("timeout")}),
+			"Can not reach the weather service. Please check your internet connection.",
+		},
+		{
+			"other",
+			errors.New("boom"),
+			"Failed to refresh: boom",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := errorMessage(tc.err); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
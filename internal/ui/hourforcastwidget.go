@@ -4,17 +4,23 @@ import (
 	"fmt"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 	"github.com/ErikKalkoken/weatherapp/internal/forecast"
+	"github.com/ErikKalkoken/weatherapp/internal/wind"
 )
 
 type HourForecastWidget struct {
 	widget.BaseWidget
 	hour          *widget.Label
 	symbol        *widget.Icon
-	temperature   *widget.Label
+	temperature   *canvas.Text
+	feelsLike     *widget.Label
 	precipitation *widget.Label
+	amount        *widget.Label
+	wind          *widget.Label
 }
 
 func NewHourForecastWidget() *HourForecastWidget {
@@ -23,14 +29,17 @@ func NewHourForecastWidget() *HourForecastWidget {
 	w := &HourForecastWidget{
 		hour:          widget.NewLabel(""),
 		symbol:        widget.NewIcon(resourceBlankSvg),
-		temperature:   widget.NewLabel(""),
+		temperature:   canvas.NewText("", theme.Color(theme.ColorNameForeground)),
+		feelsLike:     widget.NewLabel(""),
 		precipitation: p,
+		amount:        widget.NewLabel(""),
+		wind:          widget.NewLabel(""),
 	}
 	w.ExtendBaseWidget(w)
 	return w
 }
 
-func (w *HourForecastWidget) Set(f forecast.ForecastHour, icon fyne.Resource) {
+func (w *HourForecastWidget) Set(f forecast.ForecastHour, units forecast.Units, icon fyne.Resource) {
 	var text string
 	if f.IsCurrent {
 		text = "Now"
@@ -38,8 +47,11 @@ func (w *HourForecastWidget) Set(f forecast.ForecastHour, icon fyne.Resource) {
 		text = fmt.Sprintf("%02d", f.Time.Hour())
 	}
 	w.hour.SetText(text)
-	w.temperature.SetText(fmt.Sprintf("%.0f°", f.Temperature2m))
+	setTemperature(w.temperature, f.Temperature2m, units)
+	w.feelsLike.SetText(fmt.Sprintf("(%.0f°)", f.Comfort(units).FeelsLike))
 	w.precipitation.SetText(fmt.Sprintf("%d%%", f.PrecipitationProbability))
+	w.amount.SetText(formatAmount(f.Precipitation, f.Rain+f.Showers, f.Snowfall, units))
+	w.wind.SetText(fmt.Sprintf("%s %.0f", wind.Arrow(f.WindDirection10m), f.WindSpeed10m))
 	w.symbol.SetResource(icon)
 }
 
@@ -48,7 +60,10 @@ func (w *HourForecastWidget) CreateRenderer() fyne.WidgetRenderer {
 		container.NewCenter(w.hour),
 		container.NewCenter(w.symbol),
 		container.NewCenter(w.temperature),
+		container.NewCenter(w.feelsLike),
 		container.NewCenter(w.precipitation),
+		container.NewCenter(w.amount),
+		container.NewCenter(w.wind),
 	)
 	return widget.NewSimpleRenderer(c)
 }
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/weatherapp/internal/iconpack"
+)
+
+// bundledIcons is the name of the bundled icons in the icon pack selector.
+const bundledIcons = "Bundled"
+
+// showIconPacks shows a dialog for selecting the icon pack.
+func (u *ui) showIconPacks() {
+	selected, dir := currentIconPack()
+	options := []string{bundledIcons}
+	status := widget.NewLabel("")
+	status.Importance = widget.DangerImportance
+	status.Hide()
+	names, err := iconpack.List(dir)
+	if err != nil {
+		status.SetText(fmt.Sprintf("No icon packs found: %s", err))
+		status.Show()
+	}
+	options = append(options, names...)
+	if selected == "" {
+		selected = bundledIcons
+	}
+	packs := widget.NewSelect(options, nil)
+	packs.SetSelected(selected)
+	packs.OnChanged = func(name string) {
+		if err := u.selectIconPack(dir, name); err != nil {
+			status.SetText(err.Error())
+			status.Show()
+			return
+		}
+		status.Hide()
+	}
+	c := container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("Icon packs are loaded from %s", dir)),
+		packs,
+		status,
+	)
+	dialog.ShowCustom("Icon pack", "Close", c, u.window)
+}
+
+// selectIconPack switches to the icon pack with a name in a directory and shows its icons.
+func (u *ui) selectIconPack(dir, name string) error {
+	if name == bundledIcons {
+		SetIconPack("", nil)
+	} else {
+		p, err := iconpack.Load(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		SetIconPack(name, p)
+	}
+	if x, ok := u.service.Snapshot(); ok {
+		u.update(x)
+	}
+	if u.OnIconPackChanged != nil {
+		u.OnIconPackChanged()
+	}
+	return nil
+}
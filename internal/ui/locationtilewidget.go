@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/weatherapp/internal/weather"
+)
+
+// LocationTileWidget is a tile showing the current weather at a saved location.
+// Tapping it calls OnTapped.
+type LocationTileWidget struct {
+	widget.BaseWidget
+	OnTapped func()
+
+	city          *widget.Label
+	minMax        *widget.Label
+	precipitation *widget.Label
+	symbol        *widget.Icon
+	temperature   *widget.RichText
+}
+
+func NewLocationTileWidget() *LocationTileWidget {
+	p := widget.NewLabel("")
+	p.Importance = widget.HighImportance
+	w := &LocationTileWidget{
+		city:          widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+		minMax:        widget.NewLabel(""),
+		precipitation: p,
+		symbol:        widget.NewIcon(resourceBlankSvg),
+		temperature:   widget.NewRichTextFromMarkdown(""),
+	}
+	w.city.Truncation = fyne.TextTruncateEllipsis
+	w.ExtendBaseWidget(w)
+	return w
+}
+
+func (w *LocationTileWidget) Set(x weather.Site) {
+	w.city.SetText(x.Name)
+	if x.Err != nil {
+		w.symbol.SetResource(resourceBlankSvg)
+		w.temperature.ParseMarkdown("")
+		w.minMax.SetText("No data")
+		w.precipitation.SetText("")
+		return
+	}
+	c := x.Forecast.Current
+	w.symbol.SetResource(iconFromCode(c.WeatherCode, c.IsDay))
+	w.temperature.ParseMarkdown(fmt.Sprintf("## %.0f°", c.Temperature2m))
+	if len(x.Forecast.Daily) == 0 {
+		w.minMax.SetText("")
+		w.precipitation.SetText("")
+		return
+	}
+	today := x.Forecast.Daily[0]
+	w.minMax.SetText(fmt.Sprintf("%.0f° / %.0f°", today.Temperature2mMin, today.Temperature2mMax))
+	w.precipitation.SetText(fmt.Sprintf("%d%%", today.PrecipitationProbabilityMean))
+}
+
+func (w *LocationTileWidget) Tapped(_ *fyne.PointEvent) {
+	if w.OnTapped != nil {
+		w.OnTapped()
+	}
+}
+
+func (w *LocationTileWidget) CreateRenderer() fyne.WidgetRenderer {
+	bg := canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground))
+	bg.CornerRadius = theme.InputRadiusSize()
+	c := container.NewStack(
+		bg,
+		container.NewVBox(
+			w.city,
+			container.NewHBox(
+				layout.NewSpacer(),
+				w.symbol,
+				w.temperature,
+				layout.NewSpacer(),
+			),
+			container.NewHBox(
+				layout.NewSpacer(),
+				w.minMax,
+				w.precipitation,
+				layout.NewSpacer(),
+			),
+		),
+	)
+	return widget.NewSimpleRenderer(c)
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/weatherapp/internal/forecast"
+)
+
+// comparedHours is the number of hours shown side by side in the model comparison.
+const comparedHours = 48
+
+// showModels shows a comparison of the forecasts of several weather models in a dialog.
+func (u *ui) showModels() {
+	status := widget.NewLabel("Loading...")
+	body := container.NewVBox()
+	c := container.NewBorder(status, nil, nil, nil, container.NewScroll(body))
+	d := dialog.NewCustom("Model comparison", "Close", c, u.window)
+	d.Resize(fyne.NewSize(500, 500))
+	d.Show()
+	go func() {
+		r, err := u.service.CompareModels()
+		if err != nil {
+			status.SetText(fmt.Sprintf("Failed to load models: %s", err))
+			return
+		}
+		status.Hide()
+		body.Add(makeTitle("Daily agreement"))
+		body.Add(makeSpreadGrid(r))
+		body.Add(makeTitle("Hourly forecasts"))
+		body.Add(makeModelsGrid(r))
+	}()
+}
+
+// makeSpreadGrid returns a grid showing how much the models agree on each day.
+func makeSpreadGrid(r forecast.Comparison) *fyne.Container {
+	grid := container.NewGridWithColumns(4,
+		widget.NewLabelWithStyle("Day", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("Max", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("Rain", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("Confidence", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+	)
+	for _, x := range r.Days {
+		grid.Add(widget.NewLabel(x.Time.Format("Mon 02")))
+		grid.Add(widget.NewLabel(fmt.Sprintf("%.0f-%.0f°", x.Temperature2mMin, x.Temperature2mMax)))
+		grid.Add(widget.NewLabel(fmt.Sprintf("%.1f-%.1f %s", x.PrecipitationMin, x.PrecipitationMax, r.Units.Precipitation)))
+		confidence := widget.NewLabel(fmt.Sprintf("%s (±%.1f°)", x.Confidence, x.TemperatureStdDev))
+		confidence.Importance = confidenceImportance(x.Confidence)
+		grid.Add(confidence)
+	}
+	return grid
+}
+
+// makeModelsGrid returns a grid showing the hourly forecasts of the models side by side.
+func makeModelsGrid(r forecast.Comparison) *fyne.Container {
+	grid := container.NewGridWithColumns(len(r.Models) + 1)
+	grid.Add(widget.NewLabelWithStyle("Hour", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+	for _, m := range r.Models {
+		grid.Add(widget.NewLabelWithStyle(m.Model, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+	}
+	if len(r.Models) == 0 {
+		return grid
+	}
+	for i, h := range r.Models[0].Hourly {
+		if i >= comparedHours {
+			break
+		}
+		grid.Add(widget.NewLabel(h.Time.Local().Format("Mon 15h")))
+		for _, m := range r.Models {
+			if i >= len(m.Hourly) {
+				grid.Add(widget.NewLabel("-"))
+				continue
+			}
+			x := m.Hourly[i]
+			grid.Add(widget.NewLabel(fmt.Sprintf("%.0f° %.1f", x.Temperature2m, x.Precipitation)))
+		}
+	}
+	return grid
+}
+
+func confidenceImportance(c forecast.Confidence) widget.Importance {
+	switch c {
+	case forecast.ConfidenceHigh:
+		return widget.SuccessImportance
+	case forecast.ConfidenceMedium:
+		return widget.WarningImportance
+	}
+	return widget.DangerImportance
+}
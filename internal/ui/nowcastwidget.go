@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/weatherapp/internal/forecast"
+)
+
+// NowcastWidget is a compact strip showing the precipitation of the next two hours in 15 minute steps.
+// It is hidden when there is no nowcast.
+type NowcastWidget struct {
+	widget.BaseWidget
+	bars  *fyne.Container
+	text  *widget.Label
+	start *widget.Label
+	end   *widget.Label
+}
+
+func NewNowcastWidget() *NowcastWidget {
+	w := &NowcastWidget{
+		bars:  container.NewGridWithRows(1),
+		text:  widget.NewLabel(""),
+		start: widget.NewLabel("Now"),
+		end:   widget.NewLabel(""),
+	}
+	w.text.Alignment = fyne.TextAlignCenter
+	w.ExtendBaseWidget(w)
+	w.Hide()
+	return w
+}
+
+func (w *NowcastWidget) Set(r forecast.Result) {
+	w.bars.RemoveAll()
+	if len(r.Nowcast) == 0 {
+		w.Hide()
+		return
+	}
+	c := theme.Color(theme.ColorNamePrimary)
+	// Bars are scaled to 2.5 mm per 15 minutes, which is heavy rain.
+	scale := 2.5
+	if r.Units.Precipitation == "inch" {
+		scale /= 25.4
+	}
+	for _, x := range r.Nowcast {
+		bg := canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground))
+		bg.SetMinSize(fyne.NewSize(10, 16))
+		if x.IsWet(r.Units.Precipitation) {
+			f := 0.25 + 0.75*math.Min(x.Precipitation/scale, 1)
+			r, g, b, _ := c.RGBA()
+			bg.FillColor = color.NRGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: uint8(255 * f)}
+		}
+		w.bars.Add(bg)
+	}
+	last := r.Nowcast[len(r.Nowcast)-1].Time
+	w.end.SetText(formatOffset(last.Sub(time.Now())))
+	w.text.SetText(r.NowcastText(time.Now()))
+	w.Show()
+	w.Refresh()
+}
+
+// formatOffset returns a short description of a time offset, e.g. "+2h".
+func formatOffset(d time.Duration) string {
+	m := int(d.Round(15 * time.Minute).Minutes())
+	if m%60 == 0 {
+		return fmt.Sprintf("+%dh", m/60)
+	}
+	return fmt.Sprintf("+%dmin", m)
+}
+
+func (w *NowcastWidget) CreateRenderer() fyne.WidgetRenderer {
+	c := container.NewVBox(
+		w.text,
+		container.NewBorder(nil, nil, w.start, w.end, w.bars),
+	)
+	return widget.NewSimpleRenderer(c)
+}
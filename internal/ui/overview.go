@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"log"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/weatherapp/internal/weather"
+)
+
+// overview shows the current weather at many saved locations.
+type overview struct {
+	Content fyne.CanvasObject
+
+	forecastedDays int
+	last           []weather.Site // sites of the last refresh
+	sites          *weather.Sites
+	status         *widget.Label
+	tiles          []*LocationTileWidget
+}
+
+func NewOverview(sites *weather.Sites, forecastedDays int) *overview {
+	o := &overview{
+		forecastedDays: forecastedDays,
+		sites:          sites,
+		status:         widget.NewLabel(""),
+		tiles:          make([]*LocationTileWidget, sites.Len()),
+	}
+	o.status.Hide()
+	grid := container.NewGridWrap(fyne.NewSize(140, 130))
+	for i := range o.tiles {
+		t := NewLocationTileWidget()
+		grid.Add(t)
+		o.tiles[i] = t
+	}
+	o.Content = container.NewBorder(o.status, nil, nil, nil, container.NewVScroll(grid))
+	return o
+}
+
+// Refresh refreshes all tiles together.
+func (o *overview) Refresh() error {
+	sites, err := o.sites.Refresh()
+	if err != nil {
+		o.status.SetText(errorMessage(err))
+		o.status.Show()
+		return err
+	}
+	o.status.Hide()
+	o.last = sites
+	o.show(sites)
+	return nil
+}
+
+// RefreshIcons shows the sites of the last refresh again, e.g. after the icon pack has changed.
+func (o *overview) RefreshIcons() {
+	o.show(o.last)
+}
+
+func (o *overview) show(sites []weather.Site) {
+	for i, x := range sites {
+		if i >= len(o.tiles) {
+			break
+		}
+		t := o.tiles[i]
+		t.Set(x)
+		if x.Err != nil {
+			t.OnTapped = nil
+			continue
+		}
+		t.OnTapped = func() {
+			o.showSite(x)
+		}
+	}
+}
+
+// showSite shows the full forecast for a site in a new window.
+func (o *overview) showSite(x weather.Site) {
+	w := fyne.CurrentApp().NewWindow(x.Location.City)
+	u := New(w, o.sites.Service(x), o.forecastedDays, nil)
+	w.SetContent(u.Content)
+	w.Resize(fyne.NewSize(300, 600))
+	w.Show()
+	go func() {
+		if err := u.Refresh(); err != nil {
+			log.Printf("ERROR: refreshing forecast for %s: %s", x.Name, err)
+		}
+	}()
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/fyne-kx/layout"
+
+	"github.com/ErikKalkoken/weatherapp/internal/forecast"
+)
+
+// pastDays is the number of days shown at once in the past weather dialog.
+const pastDays = 7
+
+// archiveStart is the first day with observed weather in the archive.
+var archiveStart = time.Date(1940, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// showPastWeather shows the observed weather of past days in a dialog.
+// The user can pick the last day shown.
+func (u *ui) showPastWeather() {
+	days := container.NewGridWithColumns(1)
+	status := widget.NewLabel("")
+	// The archive is usually complete up to about a week ago.
+	date := newDateSelect(archiveStart.AddDate(0, 0, pastDays-1), time.Now().UTC().AddDate(0, 0, -1))
+	date.SetDate(time.Now().UTC().AddDate(0, 0, -7))
+
+	// generation is increased for each fetch, so that results of outdated fetches can be dropped.
+	var (
+		mu         sync.Mutex
+		generation int
+	)
+	// update shows the result of a fetch unless a newer fetch has been started.
+	update := func(g int, dd []forecast.ForecastDay, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if g != generation {
+			return
+		}
+		switch {
+		case err != nil:
+			status.SetText(fmt.Sprintf("Failed to load past weather: %s", err))
+		case len(dd) == 0:
+			status.SetText("No data for these days yet")
+		default:
+			status.SetText("")
+		}
+		rows := make([]fyne.CanvasObject, 0, len(dd))
+		for i := len(dd) - 1; i >= 0; i-- {
+			d := dd[i]
+			rows = append(rows, container.New(
+				layout.NewColumns(100, 50, 50, 50),
+				widget.NewLabel(d.Time.Format("Mon 02 Jan")),
+				container.NewCenter(widget.NewIcon(iconFromCode(d.WeatherCode, true))),
+				container.NewCenter(widget.NewLabel(fmt.Sprintf("%.0f°", d.Temperature2mMin))),
+				container.NewCenter(widget.NewLabel(fmt.Sprintf("%.0f°", d.Temperature2mMax))),
+			))
+		}
+		days.Objects = rows
+		days.Refresh()
+	}
+	show := func() {
+		end := date.Date()
+		mu.Lock()
+		generation++
+		g := generation
+		days.RemoveAll()
+		status.SetText("Loading...")
+		mu.Unlock()
+		go func() {
+			dd, err := u.service.PastDays(end.AddDate(0, 0, -pastDays+1), end)
+			update(g, dd, err)
+		}()
+	}
+	date.OnChanged = func(time.Time) { show() }
+	shift := func(n int) {
+		date.SetDate(date.Date().AddDate(0, 0, n))
+		show()
+	}
+	picker := container.NewBorder(
+		nil,
+		nil,
+		widget.NewButtonWithIcon("", theme.NavigateBackIcon(), func() { shift(-pastDays) }),
+		widget.NewButtonWithIcon("", theme.NavigateNextIcon(), func() { shift(pastDays) }),
+		container.NewCenter(date),
+	)
+	c := container.NewBorder(
+		container.NewVBox(picker, status),
+		nil,
+		nil,
+		nil,
+		container.NewVScroll(days),
+	)
+	d := dialog.NewCustom("Past weather", "Close", c, u.window)
+	d.Resize(fyne.NewSize(360, 450))
+	d.Show()
+	show()
+}
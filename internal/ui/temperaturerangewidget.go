@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/weatherapp/internal/forecast"
+)
+
+const (
+	rangeBarHeight = 6
+	rangeDotSize   = 10
+)
+
+// TemperatureRangeWidget is a bar showing the span of the temperatures of a day
+// relative to the temperatures of all forecasted days.
+// It can also show the current temperature as dot.
+type TemperatureRangeWidget struct {
+	widget.BaseWidget
+	bar   *canvas.LinearGradient
+	dot   *canvas.Circle
+	track *canvas.Rectangle
+
+	// positions relative to the width of the track from 0 to 1
+	start, end, current float64
+}
+
+func NewTemperatureRangeWidget() *TemperatureRangeWidget {
+	track := canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground))
+	track.CornerRadius = rangeBarHeight / 2
+	w := &TemperatureRangeWidget{
+		bar:   canvas.NewHorizontalGradient(theme.Color(theme.ColorNameForeground), theme.Color(theme.ColorNameForeground)),
+		dot:   canvas.NewCircle(theme.Color(theme.ColorNameForeground)),
+		track: track,
+	}
+	w.dot.StrokeColor = theme.Color(theme.ColorNameBackground)
+	w.dot.StrokeWidth = 2
+	w.dot.Hide()
+	w.ExtendBaseWidget(w)
+	return w
+}
+
+// Set shows the span from min to max on a scale from low to high.
+// The current temperature is hidden.
+func (w *TemperatureRangeWidget) Set(min, max, low, high float64, units forecast.Units) {
+	w.start = relativePosition(min, low, high)
+	w.end = relativePosition(max, low, high)
+	w.bar.StartColor = temperatureColor(min, units.Temperature)
+	w.bar.EndColor = temperatureColor(max, units.Temperature)
+	w.dot.Hide()
+	w.Refresh()
+}
+
+// SetCurrent shows the current temperature t on a scale from low to high.
+func (w *TemperatureRangeWidget) SetCurrent(t, low, high float64) {
+	w.current = relativePosition(t, low, high)
+	w.dot.Show()
+	w.Refresh()
+}
+
+// relativePosition returns the position of v on a scale from low to high between 0 and 1.
+func relativePosition(v, low, high float64) float64 {
+	if high <= low {
+		return 0.5
+	}
+	return min(max((v-low)/(high-low), 0), 1)
+}
+
+func (w *TemperatureRangeWidget) CreateRenderer() fyne.WidgetRenderer {
+	c := container.New(&temperatureRangeLayout{w: w}, w.track, w.bar, w.dot)
+	return widget.NewSimpleRenderer(c)
+}
+
+// temperatureRangeLayout positions the track, bar and dot of a [TemperatureRangeWidget].
+type temperatureRangeLayout struct {
+	w *TemperatureRangeWidget
+}
+
+func (l *temperatureRangeLayout) Layout(_ []fyne.CanvasObject, size fyne.Size) {
+	w := l.w
+	// The track is inset by half the dot size, so that the dot is not cut off at the ends.
+	x0 := float32(rangeDotSize) / 2
+	width := size.Width - rangeDotSize
+	y := (size.Height - rangeBarHeight) / 2
+	w.track.Move(fyne.NewPos(x0, y))
+	w.track.Resize(fyne.NewSize(width, rangeBarHeight))
+	start := x0 + width*float32(w.start)
+	w.bar.Move(fyne.NewPos(start, y))
+	w.bar.Resize(fyne.NewSize(max(width*float32(w.end-w.start), rangeBarHeight), rangeBarHeight))
+	w.dot.Move(fyne.NewPos(x0+width*float32(w.current)-rangeDotSize/2, (size.Height-rangeDotSize)/2))
+	w.dot.Resize(fyne.NewSquareSize(rangeDotSize))
+}
+
+func (l *temperatureRangeLayout) MinSize(_ []fyne.CanvasObject) fyne.Size {
+	return fyne.NewSize(60, rangeDotSize)
+}
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+
+	"github.com/ErikKalkoken/weatherapp/internal/weathercode"
+)
+
+// Theme modes
+const (
+	ThemeAuto  = "auto" // follows the system
+	ThemeLight = "light"
+	ThemeDark  = "dark"
+)
+
+var tintBackground bool
+
+// SetBackgroundTint enables tinting the background by the current weather.
+// It must be called before creating the UI.
+func SetBackgroundTint(enabled bool) {
+	tintBackground = enabled
+}
+
+type appTheme struct {
+	fyne.Theme
+	forced  bool
+	variant fyne.ThemeVariant
+}
+
+// NewTheme returns the theme of the app for a mode.
+// The theme is the default theme, which can be forced to the light or dark variant.
+func NewTheme(mode string) fyne.Theme {
+	t := &appTheme{Theme: theme.DefaultTheme()}
+	switch mode {
+	case ThemeLight:
+		t.forced, t.variant = true, theme.VariantLight
+	case ThemeDark:
+		t.forced, t.variant = true, theme.VariantDark
+	}
+	return t
+}
+
+func (t *appTheme) Color(n fyne.ThemeColorName, v fyne.ThemeVariant) color.Color {
+	if t.forced {
+		v = t.variant
+	}
+	return t.Theme.Color(n, v)
+}
+
+// conditionColors are the colors for tinting the background by weather category at day time.
+var conditionColors = map[weathercode.Category]color.NRGBA{
+	weathercode.CategoryClear:      {R: 0x42, G: 0x9a, B: 0xf5, A: 0x60},
+	weathercode.CategoryCloudy:     {R: 0x90, G: 0xa4, B: 0xae, A: 0x60},
+	weathercode.CategoryFog:        {R: 0xb0, G: 0xbe, B: 0xc5, A: 0x70},
+	weathercode.CategoryRain:       {R: 0x54, G: 0x6e, B: 0x7a, A: 0x70},
+	weathercode.CategorySnow:       {R: 0xe1, G: 0xf5, B: 0xfe, A: 0x80},
+	weathercode.CategoryConvective: {R: 0x5e, G: 0x35, B: 0xb1, A: 0x60},
+}
+
+// conditionColor returns the color for tinting the background by the weather.
+// Colors are darker at night.
+func conditionColor(code int, isDay bool) color.Color {
+	x, _ := weathercode.Lookup(code)
+	c, ok := conditionColors[x.Category]
+	if !ok {
+		return color.Transparent
+	}
+	if !isDay {
+		c.R, c.G, c.B = c.R/2, c.G/2, c.B/2
+	}
+	return c
+}
+
+// temperatureStops are the colors of the temperature scale in °C.
+var temperatureStops = []struct {
+	temperature float64
+	color       color.NRGBA
+}{
+	{-20, color.NRGBA{R: 0x6a, G: 0x5a, B: 0xcd, A: 0xff}},
+	{0, color.NRGBA{R: 0x1e, G: 0x88, B: 0xe5, A: 0xff}},
+	{10, color.NRGBA{R: 0x26, G: 0xa6, B: 0x9a, A: 0xff}},
+	{20, color.NRGBA{R: 0xf9, G: 0xa8, B: 0x25, A: 0xff}},
+	{30, color.NRGBA{R: 0xef, G: 0x6c, B: 0x00, A: 0xff}},
+	{40, color.NRGBA{R: 0xc6, G: 0x28, B: 0x28, A: 0xff}},
+}
+
+// temperatureColor returns the color for a temperature on a scale from cold to hot.
+func temperatureColor(t float64, unit string) color.Color {
+	if unit == "°F" {
+		t = (t - 32) * 5 / 9
+	}
+	first, last := temperatureStops[0], temperatureStops[len(temperatureStops)-1]
+	if t <= first.temperature {
+		return first.color
+	}
+	if t >= last.temperature {
+		return last.color
+	}
+	for i, b := range temperatureStops[1:] {
+		a := temperatureStops[i]
+		if t > b.temperature {
+			continue
+		}
+		f := (t - a.temperature) / (b.temperature - a.temperature)
+		mix := func(x, y uint8) uint8 {
+			return uint8(float64(x) + f*(float64(y)-float64(x)))
+		}
+		return color.NRGBA{R: mix(a.color.R, b.color.R), G: mix(a.color.G, b.color.G), B: mix(a.color.B, b.color.B), A: 0xff}
+	}
+	return last.color
+}
@@ -1,7 +1,10 @@
 package ui
 
 import (
-	"net/http"
+	"fmt"
+	"image/color"
+	"log"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -9,31 +12,46 @@ import (
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 	"github.com/ErikKalkoken/weatherapp/internal/forecast"
-	"github.com/ErikKalkoken/weatherapp/internal/location"
+	"github.com/ErikKalkoken/weatherapp/internal/store"
+	"github.com/ErikKalkoken/weatherapp/internal/weather"
 )
 
-const (
-	forecastedHours = 24
-	forecastedDays  = 10
-)
+const forecastedHours = 24
 
 type ui struct {
 	Content fyne.CanvasObject
+	// OnIconPackChanged is called after the user selected another icon pack.
+	OnIconPackChanged func()
 
-	httpClient *http.Client
+	history    *store.Store // nil when disabled
+	service    *weather.Service
 	window     fyne.Window
+	background *canvas.LinearGradient
+	alerts     *AlertsWidget
+	warnings   *WarningsWidget
 	current    *CurrentWeatherWidget
+	summary    *widget.Label
+	wind       *WindWidget
+	errorLabel *widget.Label
+	nowcast    *NowcastWidget
 	hours      []*HourForecastWidget
 	days       []*DayForecastWidget
 }
 
-func New(w fyne.Window, httpClient *http.Client) *ui {
-	loadWeatherIcons()
+func New(w fyne.Window, service *weather.Service, forecastedDays int, history *store.Store) *ui {
 	u := &ui{
+		alerts:     NewAlertsWidget(),
+		background: canvas.NewVerticalGradient(color.Transparent, color.Transparent),
+		warnings:   NewWarningsWidget(),
 		current:    NewCurrentWeatherWidget(),
+		summary:    widget.NewLabel(""),
+		wind:       NewWindWidget(),
+		errorLabel: widget.NewLabel(""),
+		nowcast:    NewNowcastWidget(),
 		days:       make([]*DayForecastWidget, forecastedDays),
+		history:    history,
 		hours:      make([]*HourForecastWidget, forecastedHours+1),
-		httpClient: httpClient,
+		service:    service,
 		window:     w,
 	}
 
@@ -58,20 +76,35 @@ func New(w fyne.Window, httpClient *http.Client) *ui {
 		u.days[i] = d
 	}
 	daysBox := container.NewBorder(
-		makeTitle("10-Day Forecast"),
+		makeTitle(fmt.Sprintf("%d-Day Forecast", forecastedDays)),
 		nil,
 		nil,
 		nil,
 		container.NewVScroll(dayGrid),
 	)
 	c := container.NewBorder(
-		container.NewVBox(u.current, hoursBox),
+		container.NewVBox(u.errorLabel, u.warnings, u.alerts, u.current, u.summary, u.wind, u.nowcast, hoursBox),
 		nil,
 		nil,
 		nil,
 		daysBox,
 	)
-	u.Content = c
+	u.errorLabel.Importance = widget.DangerImportance
+	u.errorLabel.Wrapping = fyne.TextWrapWord
+	u.errorLabel.Hide()
+	u.summary.Alignment = fyne.TextAlignCenter
+	u.summary.Wrapping = fyne.TextWrapWord
+	u.Content = container.NewStack(u.background, c)
+	w.SetMainMenu(fyne.NewMainMenu(
+		fyne.NewMenu("View",
+			fyne.NewMenuItem("Past weather", u.showPastWeather),
+			fyne.NewMenuItem("Model comparison", u.showModels),
+			fyne.NewMenuItem("Forecast accuracy", u.showAccuracy),
+		),
+		fyne.NewMenu("Settings",
+			fyne.NewMenuItem("Icon pack", u.showIconPacks),
+		),
+	))
 	return u
 }
 
@@ -80,28 +113,67 @@ func makeTitle(s string) *fyne.Container {
 }
 
 func (u *ui) Refresh() error {
-	var err error
-	loc, err := location.Get(u.httpClient)
+	x, err := u.service.Refresh()
 	if err != nil {
+		u.errorLabel.SetText(errorMessage(err))
+		u.errorLabel.Show()
 		return err
 	}
-	current, hours, days, err := forecast.Get(u.httpClient, loc.Latitude, loc.Longitude)
-	if err != nil {
-		return err
+	u.errorLabel.Hide()
+	u.update(x)
+	return nil
+}
+
+// update shows the weather of a snapshot.
+func (u *ui) update(x weather.Snapshot) {
+	current := x.Forecast.Current
+	u.warnings.Set(x.Warnings)
+	u.alerts.Set(x.Alerts)
+	u.current.Set(x.Location, current)
+	if tintBackground {
+		u.background.StartColor = conditionColor(current.WeatherCode, current.IsDay)
+		u.background.Refresh()
 	}
-	u.current.Set(loc, current)
-	u.hours[0].Set(current, iconFromCode(current.WeatherCode, current.IsDay))
-	for i, f := range hours {
+	u.current.SetComfort(current, x.Forecast.Units)
+	u.current.SetYesterday(x.Forecast)
+	u.summary.SetText(x.Forecast.Summary(time.Now()))
+	u.wind.Set(current, x.Forecast.Units)
+	u.nowcast.Set(x.Forecast)
+	u.refreshLastYear(x.Forecast.Daily)
+	u.hours[0].Set(current, x.Forecast.Units, iconFromCode(current.WeatherCode, current.IsDay))
+	for i, f := range x.Forecast.Hourly {
 		if i+1 >= len(u.hours) {
 			break
 		}
-		u.hours[i+1].Set(f, iconFromCode(f.WeatherCode, f.IsDay))
+		u.hours[i+1].Set(f, x.Forecast.Units, iconFromCode(f.WeatherCode, f.IsDay))
 	}
-	for i, f := range days {
-		if i >= len(u.days) {
-			break
+	days := x.Forecast.Daily[:min(len(x.Forecast.Daily), len(u.days))]
+	if len(days) > 0 {
+		low, high := days[0].Temperature2mMin, days[0].Temperature2mMax
+		for _, f := range days {
+			low = min(low, f.Temperature2mMin, current.Temperature2m)
+			high = max(high, f.Temperature2mMax, current.Temperature2m)
+		}
+		for i, f := range days {
+			u.days[i].Set(f, x.Forecast.Units, iconFromCode(f.WeatherCode, true), low, high)
 		}
-		u.days[i].Set(f, iconFromCode(f.WeatherCode, true))
+		u.days[0].SetCurrent(current.Temperature2m, low, high)
 	}
-	return nil
+}
+
+// refreshLastYear updates the comparison of today with the same day last year.
+func (u *ui) refreshLastYear(days []forecast.ForecastDay) {
+	if len(days) == 0 {
+		u.current.HideLastYear()
+		return
+	}
+	d, ok, err := u.service.LastYear()
+	if err != nil {
+		log.Printf("ERROR: fetching weather of last year: %s", err)
+	}
+	if !ok {
+		u.current.HideLastYear()
+		return
+	}
+	u.current.SetLastYear(days[0], d)
 }
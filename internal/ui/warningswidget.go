@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/weatherapp/internal/cap"
+)
+
+// WarningsWidget shows official weather warnings. It is hidden when there are no warnings.
+type WarningsWidget struct {
+	widget.BaseWidget
+	box *fyne.Container
+}
+
+func NewWarningsWidget() *WarningsWidget {
+	w := &WarningsWidget{
+		box: container.NewVBox(),
+	}
+	w.ExtendBaseWidget(w)
+	w.Hide()
+	return w
+}
+
+func (w *WarningsWidget) Set(ww []cap.Warning) {
+	w.box.RemoveAll()
+	for _, x := range ww {
+		title := x.Headline
+		if title == "" {
+			title = x.Event
+		}
+		w.box.Add(widget.NewRichText(&widget.TextSegment{Text: title, Style: widget.RichTextStyleStrong}))
+		info := widget.NewLabel(fmt.Sprintf("%s · %s", x.Severity, validity(x)))
+		info.Importance = warningImportance(x.Severity)
+		w.box.Add(info)
+		if x.Instruction != "" {
+			l := widget.NewLabel(x.Instruction)
+			l.Wrapping = fyne.TextWrapWord
+			w.box.Add(l)
+		}
+	}
+	if len(ww) == 0 {
+		w.Hide()
+	} else {
+		w.Show()
+	}
+	w.Refresh()
+}
+
+// validity returns a description of the validity window of a warning.
+func validity(x cap.Warning) string {
+	const layout = "Mon 15:04"
+	if x.End.IsZero() {
+		return fmt.Sprintf("from %s", x.Start.Local().Format(layout))
+	}
+	return fmt.Sprintf("%s - %s", x.Start.Local().Format(layout), x.End.Local().Format(layout))
+}
+
+func warningImportance(severity string) widget.Importance {
+	switch severity {
+	case "Extreme", "Severe":
+		return widget.DangerImportance
+	case "Moderate":
+		return widget.WarningImportance
+	}
+	return widget.MediumImportance
+}
+
+func (w *WarningsWidget) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(w.box)
+}
@@ -1,114 +1,95 @@
 package ui
 
 import (
+	"sync"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/theme"
+	"github.com/ErikKalkoken/weatherapp/internal/iconpack"
+	"github.com/ErikKalkoken/weatherapp/internal/weathercode"
 )
 
-type iconName uint
-
-const (
-	undefined iconName = iota
-	cloudy
-	dust
-	fog
-	hail
-	hurricane
-	lightning
-	lightningRainy
-	night
-	nightPartlyCloudy
-	partlyCloudy
-	partlyRainy
-	partlySnowy
-	partlySnowyRainy
-	partyLightning
-	pouring
-	rainy
-	snowy
-	snowyHeavy
-	snowyRainy
-	sunny
-	windy
+var (
+	iconsMu      sync.Mutex
+	iconPack     *iconpack.Pack // nil for the bundled icons
+	iconPackName string         // name of the directory of the icon pack
+	iconPacksDir string         // directory with the icon packs, which can be selected in the settings
+	weatherIcons map[weathercode.Icon]fyne.Resource
 )
 
-type weatherCodeMapping struct {
-	description string
-	icon        iconName
-	iconNight   iconName // alternate to be used at night (when defined)
+// SetIconPack sets an icon pack with the name of its directory for the weather icons.
+// Icons missing in the pack are taken from the bundled icons. p is nil for the bundled icons.
+// Widgets show the icons of the new pack when they are updated the next time.
+func SetIconPack(name string, p *iconpack.Pack) {
+	iconsMu.Lock()
+	defer iconsMu.Unlock()
+	iconPack = p
+	iconPackName = name
+	weatherIcons = nil
 }
 
-var weatherCodeMappings = map[int]weatherCodeMapping{
-	0:  {"clear sky", sunny, night},
-	1:  {"mainly clear", sunny, night},
-	2:  {"partly cloudy", partlyCloudy, nightPartlyCloudy},
-	3:  {"overcast", cloudy, undefined},
-	45: {"fog", fog, undefined},
-	48: {"depositing rime fog", fog, undefined},
-	51: {"light drizzle", rainy, rainy},
-	52: {"moderate drizzle", rainy, undefined},
-	53: {"dense drizzle", rainy, undefined},
-	56: {"light freezing drizzle", snowyRainy, undefined},
-	57: {"dense freezing drizzle", snowyRainy, undefined},
-	61: {"slight rain", rainy, undefined},
-	63: {"moderate rain", pouring, undefined},
-	65: {"heavy rain", pouring, undefined},
-	66: {"light freezing rain", snowyRainy, rainy},
-	67: {"heavy freezing rain", pouring, undefined},
-	71: {"slight snow fall", snowy, snowy},
-	73: {"moderate snow fall", snowy, undefined},
-	75: {"heavy snow fall", snowyHeavy, undefined},
-	77: {"snow grains", snowy, undefined},
-	80: {"slight rain showers", rainy, undefined},
-	81: {"moderate rain showers", rainy, undefined},
-	83: {"violent rain showers", pouring, undefined},
-	85: {"slight snow showers", snowy, undefined},
-	86: {"heavy snow showers", snowyHeavy, undefined},
-	95: {"thunderstorms", lightning, undefined},
-	96: {"thunderstorms with slight hail", lightningRainy, undefined},
-	99: {"thunderstorms with heavy hail", hail, undefined},
+// SetIconPacksDir sets the directory with the icon packs, which can be selected in the settings.
+func SetIconPacksDir(dir string) {
+	iconsMu.Lock()
+	defer iconsMu.Unlock()
+	iconPacksDir = dir
 }
 
-var weatherIcons map[iconName]fyne.Resource
+// currentIconPack returns the name of the current icon pack and the directory of all icon packs.
+func currentIconPack() (name string, dir string) {
+	iconsMu.Lock()
+	defer iconsMu.Unlock()
+	return iconPackName, iconPacksDir
+}
 
+// loadWeatherIcons loads the icons of the current icon pack. The caller must hold iconsMu.
 func loadWeatherIcons() {
-	weatherIcons = map[iconName]fyne.Resource{
-		cloudy:            theme.NewThemedResource(resourceWeatherCloudySvg),
-		dust:              theme.NewThemedResource(resourceWeatherDustSvg),
-		fog:               theme.NewThemedResource(resourceWeatherFogSvg),
-		hail:              theme.NewThemedResource(resourceWeatherHailSvg),
-		lightning:         theme.NewThemedResource(resourceWeatherLightningSvg),
-		lightningRainy:    theme.NewThemedResource(resourceWeatherLightningRainySvg),
-		partyLightning:    theme.NewThemedResource(resourceWeatherPartlyLightningSvg),
-		hurricane:         theme.NewThemedResource(resourceWeatherHurricaneOutlineSvg),
-		night:             theme.NewThemedResource(resourceWeatherNightSvg),
-		nightPartlyCloudy: theme.NewThemedResource(resourceWeatherNightPartlyCloudySvg),
-		partlyCloudy:      theme.NewThemedResource(resourceWeatherPartlyCloudySvg),
-		partlyRainy:       theme.NewThemedResource(resourceWeatherPartlyRainySvg),
-		partlySnowy:       theme.NewThemedResource(resourceWeatherPartlySnowySvg),
-		partlySnowyRainy:  theme.NewThemedResource(resourceWeatherPartlySnowyRainySvg),
-		pouring:           theme.NewThemedResource(resourceWeatherPouringSvg),
-		rainy:             theme.NewThemedResource(resourceWeatherRainySvg),
-		snowy:             theme.NewThemedResource(resourceWeatherSnowySvg),
-		snowyHeavy:        theme.NewThemedResource(resourceWeatherSnowyHeavySvg),
-		snowyRainy:        theme.NewThemedResource(resourceWeatherSnowyRainySvg),
-		sunny:             theme.NewThemedResource(resourceWeatherSunnySvg),
-		windy:             theme.NewThemedResource(resourceWeatherWindySvg),
-		undefined:         theme.QuestionIcon(),
+	weatherIcons = map[weathercode.Icon]fyne.Resource{
+		weathercode.IconCloudy:            theme.NewThemedResource(resourceWeatherCloudySvg),
+		weathercode.IconDust:              theme.NewThemedResource(resourceWeatherDustSvg),
+		weathercode.IconFog:               theme.NewThemedResource(resourceWeatherFogSvg),
+		weathercode.IconHail:              theme.NewThemedResource(resourceWeatherHailSvg),
+		weathercode.IconHurricane:         theme.NewThemedResource(resourceWeatherHurricaneOutlineSvg),
+		weathercode.IconLightning:         theme.NewThemedResource(resourceWeatherLightningSvg),
+		weathercode.IconLightningRainy:    theme.NewThemedResource(resourceWeatherLightningRainySvg),
+		weathercode.IconNight:             theme.NewThemedResource(resourceWeatherNightSvg),
+		weathercode.IconNightPartlyCloudy: theme.NewThemedResource(resourceWeatherNightPartlyCloudySvg),
+		weathercode.IconPartlyCloudy:      theme.NewThemedResource(resourceWeatherPartlyCloudySvg),
+		weathercode.IconPartlyLightning:   theme.NewThemedResource(resourceWeatherPartlyLightningSvg),
+		weathercode.IconPartlyRainy:       theme.NewThemedResource(resourceWeatherPartlyRainySvg),
+		weathercode.IconPartlySnowy:       theme.NewThemedResource(resourceWeatherPartlySnowySvg),
+		weathercode.IconPartlySnowyRainy:  theme.NewThemedResource(resourceWeatherPartlySnowyRainySvg),
+		weathercode.IconPouring:           theme.NewThemedResource(resourceWeatherPouringSvg),
+		weathercode.IconRainy:             theme.NewThemedResource(resourceWeatherRainySvg),
+		weathercode.IconSnowy:             theme.NewThemedResource(resourceWeatherSnowySvg),
+		weathercode.IconSnowyHeavy:        theme.NewThemedResource(resourceWeatherSnowyHeavySvg),
+		weathercode.IconSnowyRainy:        theme.NewThemedResource(resourceWeatherSnowyRainySvg),
+		weathercode.IconSunny:             theme.NewThemedResource(resourceWeatherSunnySvg),
+		weathercode.IconWindy:             theme.NewThemedResource(resourceWeatherWindySvg),
+		weathercode.IconUnknown:           theme.QuestionIcon(),
+	}
+	if iconPack == nil {
+		return
+	}
+	for icon, x := range iconPack.Icons {
+		var r fyne.Resource = fyne.NewStaticResource(x.Name, x.Content)
+		if iconPack.Monochrome && x.IsSVG() {
+			r = theme.NewThemedResource(r)
+		}
+		weatherIcons[icon] = r
 	}
 }
 
 func iconFromCode(code int, isDay bool) fyne.Resource {
-	m := weatherCodeMappings[code]
-	var short iconName
-	if !isDay && m.iconNight != undefined {
-		short = m.iconNight
-	} else {
-		short = m.icon
+	iconsMu.Lock()
+	defer iconsMu.Unlock()
+	if weatherIcons == nil {
+		loadWeatherIcons()
 	}
-	r, ok := weatherIcons[short]
+	x, _ := weathercode.Lookup(code)
+	r, ok := weatherIcons[x.Icon(isDay)]
 	if !ok {
-		r = weatherIcons[undefined]
+		r = weatherIcons[weathercode.IconUnknown]
 	}
 	return r
 }
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/theme"
+
+	"github.com/ErikKalkoken/weatherapp/internal/iconpack"
+	"github.com/ErikKalkoken/weatherapp/internal/weathercode"
+)
+
+func TestWeatherIcons(t *testing.T) {
+	test.NewApp()
+	pack, err := iconpack.Load("../iconpack/testdata/packs/mono")
+	if err != nil {
+		t.Fatal(err)
+	}
+	packs := map[string]*iconpack.Pack{"bundled": nil, "mono": pack}
+	for name, p := range packs {
+		t.Run(name, func(t *testing.T) {
+			SetIconPack(name, p)
+			t.Cleanup(func() { SetIconPack("", nil) })
+			iconFromCode(0, true) // loads the icons
+			for _, icon := range weathercode.Icons() {
+				if _, ok := weatherIcons[icon]; !ok {
+					t.Errorf("icon %s not loaded", icon)
+				}
+			}
+			unknown := theme.QuestionIcon()
+			for _, x := range weathercode.All() {
+				for _, isDay := range []bool{true, false} {
+					r := iconFromCode(x.Code, isDay)
+					if r == nil || r == unknown {
+						t.Errorf("code %d (day: %v): no icon", x.Code, isDay)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSetIconPack(t *testing.T) {
+	test.NewApp()
+	pack, err := iconpack.Load("../iconpack/testdata/packs/mono")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetIconPack("", nil) })
+	SetIconPack("", nil)
+	// Themed resources are named after their source with a prefix.
+	if got := iconFromCode(0, true).Name(); !strings.HasSuffix(got, resourceWeatherSunnySvg.Name()) {
+		t.Errorf("bundled: got %s", got)
+	}
+	SetIconPack("mono", pack)
+	if got := iconFromCode(0, true).Name(); !strings.HasSuffix(got, "_sunny.svg") {
+		t.Errorf("pack: got %s", got)
+	}
+	if got := iconFromCode(3, true).Name(); !strings.HasSuffix(got, resourceWeatherCloudySvg.Name()) {
+		t.Errorf("missing in pack: got %s", got)
+	}
+	if name, _ := currentIconPack(); name != "mono" {
+		t.Errorf("got name %q", name)
+	}
+}
@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/weatherapp/internal/forecast"
+	"github.com/ErikKalkoken/weatherapp/internal/wind"
+)
+
+const compassSize = 80
+
+// WindWidget shows the current wind with a compass rose and the Beaufort scale.
+type WindWidget struct {
+	widget.BaseWidget
+	compass  *compass
+	speed    *widget.Label
+	gusts    *widget.Label
+	beaufort *widget.Label
+}
+
+func NewWindWidget() *WindWidget {
+	w := &WindWidget{
+		compass:  newCompass(),
+		speed:    widget.NewLabel(""),
+		gusts:    widget.NewLabel(""),
+		beaufort: widget.NewLabel(""),
+	}
+	w.ExtendBaseWidget(w)
+	return w
+}
+
+func (w *WindWidget) Set(f forecast.ForecastHour, units forecast.Units) {
+	b := f.Beaufort(units)
+	if b == 0 {
+		w.speed.SetText("Calm")
+		w.compass.hideArrow()
+	} else {
+		w.speed.SetText(fmt.Sprintf(
+			"Wind %.0f %s from %s", f.WindSpeed10m, units.WindSpeed, wind.CompassPoint(f.WindDirection10m),
+		))
+		w.compass.setDirection(f.WindDirection10m)
+	}
+	w.gusts.SetText(fmt.Sprintf("Gusts %.0f %s", f.WindGusts10m, units.WindSpeed))
+	w.beaufort.SetText(fmt.Sprintf("Beaufort %d · %s", b, wind.BeaufortDescription(b)))
+}
+
+func (w *WindWidget) CreateRenderer() fyne.WidgetRenderer {
+	c := container.NewCenter(container.NewHBox(
+		w.compass.container,
+		container.NewVBox(w.speed, w.gusts, w.beaufort),
+	))
+	return widget.NewSimpleRenderer(c)
+}
+
+// compass is a compass rose with an arrow showing where the wind blows to.
+type compass struct {
+	container *fyne.Container
+	direction float64 // direction the wind comes from in degrees
+
+	ring   *canvas.Circle
+	points []*canvas.Text // N, E, S, W
+	shaft  *canvas.Line
+	wings  [2]*canvas.Line
+}
+
+func newCompass() *compass {
+	fg := theme.Color(theme.ColorNameForeground)
+	c := &compass{
+		ring:  canvas.NewCircle(color.Transparent),
+		shaft: canvas.NewLine(theme.Color(theme.ColorNamePrimary)),
+	}
+	c.ring.StrokeColor = theme.Color(theme.ColorNameDisabled)
+	c.ring.StrokeWidth = 1
+	c.shaft.StrokeWidth = 2
+	for i := range c.wings {
+		c.wings[i] = canvas.NewLine(theme.Color(theme.ColorNamePrimary))
+		c.wings[i].StrokeWidth = 2
+	}
+	objects := []fyne.CanvasObject{c.ring, c.shaft, c.wings[0], c.wings[1]}
+	for _, s := range []string{"N", "E", "S", "W"} {
+		t := canvas.NewText(s, fg)
+		t.TextSize = theme.CaptionTextSize()
+		c.points = append(c.points, t)
+		objects = append(objects, t)
+	}
+	c.container = container.New(c, objects...)
+	c.hideArrow()
+	return c
+}
+
+func (c *compass) setDirection(degrees float64) {
+	c.direction = degrees
+	c.shaft.Show()
+	c.wings[0].Show()
+	c.wings[1].Show()
+	c.container.Refresh()
+}
+
+func (c *compass) hideArrow() {
+	c.shaft.Hide()
+	c.wings[0].Hide()
+	c.wings[1].Hide()
+}
+
+// Layout positions the parts of the compass. It implements [fyne.Layout].
+func (c *compass) Layout(_ []fyne.CanvasObject, size fyne.Size) {
+	d := min(size.Width, size.Height)
+	center := fyne.NewPos(size.Width/2, size.Height/2)
+	r := float64(d)/2 - float64(theme.CaptionTextSize())
+	c.ring.Move(fyne.NewPos(center.X-float32(r), center.Y-float32(r)))
+	c.ring.Resize(fyne.NewSquareSize(float32(2 * r)))
+	// point returns the position at a bearing in degrees and a distance from the center.
+	point := func(bearing, distance float64) fyne.Position {
+		a := bearing * math.Pi / 180
+		return fyne.NewPos(center.X+float32(distance*math.Sin(a)), center.Y-float32(distance*math.Cos(a)))
+	}
+	for i, t := range c.points {
+		s := t.MinSize()
+		p := point(float64(i*90), r+float64(theme.CaptionTextSize())/2)
+		t.Move(fyne.NewPos(p.X-s.Width/2, p.Y-s.Height/2))
+	}
+	tail := point(c.direction, r*0.7)
+	head := point(c.direction+180, r*0.7)
+	c.shaft.Position1, c.shaft.Position2 = tail, head
+	for i, angle := range []float64{150, -150} {
+		c.wings[i].Position1 = head
+		c.wings[i].Position2 = point(c.direction+180+angle, r*0.3)
+		c.wings[i].Position2 = c.wings[i].Position2.Add(head).Subtract(center)
+	}
+}
+
+func (c *compass) MinSize(_ []fyne.CanvasObject) fyne.Size {
+	return fyne.NewSquareSize(compassSize)
+}
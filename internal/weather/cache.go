@@ -0,0 +1,78 @@
+package weather
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cachedSnapshot is the content of a cache file.
+type cachedSnapshot struct {
+	Key      string   `json:"key"`
+	Snapshot Snapshot `json:"snapshot"`
+}
+
+// RefreshCached returns the snapshot from the cache file at path when it is younger than maxAge
+// and was stored for the same key. Otherwise it refreshes the weather and updates the cache file.
+// The key identifies the settings a snapshot depends on, e.g. the location and units.
+// This allows short-lived processes to share one fetch.
+func (s *Service) RefreshCached(path, key string, maxAge time.Duration) (Snapshot, error) {
+	x, err := readSnapshot(path, key)
+	if err == nil && time.Since(x.UpdatedAt) < maxAge {
+		s.mu.Lock()
+		s.snapshot = x
+		s.hasData = true
+		s.mu.Unlock()
+		return x, nil
+	}
+	x, err = s.Refresh()
+	if err != nil {
+		return Snapshot{}, err
+	}
+	if err := writeSnapshot(path, key, x); err != nil {
+		return Snapshot{}, err
+	}
+	return x, nil
+}
+
+// readSnapshot returns the snapshot from a cache file. It reports an error when the snapshot was stored for another key.
+func readSnapshot(path, key string) (Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	var c cachedSnapshot
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Snapshot{}, err
+	}
+	if c.Key != key {
+		return Snapshot{}, fmt.Errorf("cache file %s: key mismatch", path)
+	}
+	return c.Snapshot, nil
+}
+
+// writeSnapshot writes a snapshot with its key to a cache file atomically.
+func writeSnapshot(path, key string, x Snapshot) error {
+	data, err := json.Marshal(cachedSnapshot{Key: key, Snapshot: x})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
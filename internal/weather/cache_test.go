@@ -0,0 +1,52 @@
+package weather
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ErikKalkoken/weatherapp/internal/location"
+)
+
+func TestRefreshCached(t *testing.T) {
+	errRefresh := errors.New("refresh")
+	var refreshes int
+	s := New(nil, func() (location.Location, error) {
+		refreshes++
+		return location.Location{}, errRefresh
+	})
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	x := Snapshot{Location: location.Location{City: "Berlin"}, UpdatedAt: time.Now()}
+	if err := writeSnapshot(path, "berlin|celsius", x); err != nil {
+		t.Fatal(err)
+	}
+	t.Run("hit", func(t *testing.T) {
+		refreshes = 0
+		got, err := s.RefreshCached(path, "berlin|celsius", time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Location.City != "Berlin" || refreshes != 0 {
+			t.Errorf("got %+v after %d refreshes", got.Location, refreshes)
+		}
+	})
+	t.Run("other key", func(t *testing.T) {
+		refreshes = 0
+		if _, err := s.RefreshCached(path, "berlin|fahrenheit", time.Minute); !errors.Is(err, errRefresh) {
+			t.Errorf("got %v, want refresh", err)
+		}
+		if refreshes != 1 {
+			t.Errorf("got %d refreshes, want 1", refreshes)
+		}
+	})
+	t.Run("expired", func(t *testing.T) {
+		refreshes = 0
+		if _, err := s.RefreshCached(path, "berlin|celsius", time.Nanosecond); !errors.Is(err, errRefresh) {
+			t.Errorf("got %v, want refresh", err)
+		}
+		if refreshes != 1 {
+			t.Errorf("got %d refreshes, want 1", refreshes)
+		}
+	})
+}
@@ -0,0 +1,22 @@
+package weather
+
+import (
+	"errors"
+
+	"github.com/ErikKalkoken/weatherapp/internal/forecast"
+)
+
+var ErrNoModels = errors.New("no models configured")
+
+// CompareModels returns the forecasts of the configured weather models
+// for the location of the latest snapshot.
+func (s *Service) CompareModels() (forecast.Comparison, error) {
+	if len(s.Models) == 0 {
+		return forecast.Comparison{}, ErrNoModels
+	}
+	x, ok := s.Snapshot()
+	if !ok {
+		return forecast.Comparison{}, ErrNoData
+	}
+	return s.forecasts.Compare(x.Location.Latitude, x.Location.Longitude, s.Models)
+}
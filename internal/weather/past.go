@@ -0,0 +1,75 @@
+package weather
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ErikKalkoken/weatherapp/internal/forecast"
+)
+
+var (
+	ErrNoArchive = errors.New("no archive configured")
+	ErrNoData    = errors.New("no data yet")
+)
+
+// PastDays returns the observed weather at the location of the latest snapshot
+// for all days from start to end (inclusive).
+func (s *Service) PastDays(start, end time.Time) ([]forecast.ForecastDay, error) {
+	if s.Archive == nil {
+		return nil, ErrNoArchive
+	}
+	x, ok := s.Snapshot()
+	if !ok {
+		return nil, ErrNoData
+	}
+	return s.Archive.Get(x.Location.Latitude, x.Location.Longitude, start, end)
+}
+
+// How long to cache the weather of last year when there is none.
+const (
+	lastYearEmptyTTL  = 6 * time.Hour    // when the archive has no data for that day
+	lastYearFailedTTL = 10 * time.Minute // when fetching from the archive failed
+)
+
+// lastYearResult is a cached result of fetching the weather of last year.
+type lastYearResult struct {
+	day     forecast.ForecastDay
+	ok      bool
+	err     error
+	expires time.Time // zero when it does not expire
+}
+
+// LastYear returns the observed weather on the same day last year at the location of the latest snapshot.
+// It reports false when there is no data for that day.
+// Results are cached. Empty and failed results are cached only shortly, so that the archive is not asked on every refresh.
+func (s *Service) LastYear() (forecast.ForecastDay, bool, error) {
+	if s.Archive == nil {
+		return forecast.ForecastDay{}, false, ErrNoArchive
+	}
+	x, ok := s.Snapshot()
+	if !ok {
+		return forecast.ForecastDay{}, false, ErrNoData
+	}
+	day := x.UpdatedAt.UTC().Truncate(24*time.Hour).AddDate(-1, 0, 0)
+	key := fmt.Sprintf("%.2f,%.2f|%s", x.Location.Latitude, x.Location.Longitude, day.Format(time.DateOnly))
+	s.mu.RLock()
+	r, ok := s.lastYear[key]
+	s.mu.RUnlock()
+	if ok && (r.expires.IsZero() || time.Now().Before(r.expires)) {
+		return r.day, r.ok, r.err
+	}
+	days, err := s.Archive.Get(x.Location.Latitude, x.Location.Longitude, day, day)
+	switch {
+	case err != nil:
+		r = lastYearResult{err: err, expires: time.Now().Add(lastYearFailedTTL)}
+	case len(days) == 0:
+		r = lastYearResult{expires: time.Now().Add(lastYearEmptyTTL)}
+	default:
+		r = lastYearResult{day: days[0], ok: true}
+	}
+	s.mu.Lock()
+	s.lastYear = map[string]lastYearResult{key: r}
+	s.mu.Unlock()
+	return r.day, r.ok, r.err
+}
@@ -0,0 +1,128 @@
+package weather
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ErikKalkoken/weatherapp/internal/apierror"
+	"github.com/ErikKalkoken/weatherapp/internal/archive"
+	"github.com/ErikKalkoken/weatherapp/internal/location"
+)
+
+func TestLastYear(t *testing.T) {
+	var requests int
+	var status int
+	var empty bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if status != http.StatusOK {
+			http.Error(w, "unavailable", status)
+			return
+		}
+		day := r.URL.Query().Get("start_date")
+		v := "12.5"
+		if empty {
+			v = "null"
+		}
+		fmt.Fprintf(w, `{"daily":{"time":[%q],"temperature_2m_max":[%s],"temperature_2m_min":[%s],"weather_code":[%s],`+
+			`"wind_gusts_10m_max":[%s],"precipitation_sum":[0],"rain_sum":[0],"snowfall_sum":[0],"precipitation_hours":[0]}}`,
+			day, v, v, v, v)
+	}))
+	t.Cleanup(srv.Close)
+	newService := func() *Service {
+		s := New(nil, nil)
+		s.Archive = archive.NewClient(srv.Client())
+		s.Archive.BaseURL = srv.URL
+		s.snapshot = Snapshot{Location: location.Location{Latitude: 52.52, Longitude: 13.41}, UpdatedAt: time.Now()}
+		s.hasData = true
+		return s
+	}
+	// expire lets the cached result of s expire.
+	expire := func(s *Service) {
+		for k, r := range s.lastYear {
+			r.expires = time.Now().Add(-time.Second)
+			s.lastYear[k] = r
+		}
+	}
+	t.Run("found", func(t *testing.T) {
+		requests, status, empty = 0, http.StatusOK, false
+		s := newService()
+		for range 2 {
+			d, ok, err := s.LastYear()
+			if err != nil || !ok || d.Temperature2mMax != 12.5 {
+				t.Fatalf("got %+v, %v, %v", d, ok, err)
+			}
+		}
+		if requests != 1 {
+			t.Errorf("got %d requests, want 1", requests)
+		}
+		if r := s.lastYear; len(r) != 1 {
+			t.Fatalf("got %d cached results", len(r))
+		}
+		for _, r := range s.lastYear {
+			if !r.expires.IsZero() {
+				t.Errorf("found result expires at %s", r.expires)
+			}
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		requests, status, empty = 0, http.StatusOK, true
+		s := newService()
+		for range 2 {
+			if _, ok, err := s.LastYear(); err != nil || ok {
+				t.Fatalf("got %v, %v", ok, err)
+			}
+		}
+		if requests != 1 {
+			t.Errorf("got %d requests, want 1", requests)
+		}
+		for _, r := range s.lastYear {
+			if d := time.Until(r.expires); d <= 0 || d > lastYearEmptyTTL {
+				t.Errorf("empty result expires in %s", d)
+			}
+		}
+		empty = false
+		expire(s)
+		if _, ok, err := s.LastYear(); err != nil || !ok {
+			t.Errorf("after expiry: got %v, %v", ok, err)
+		}
+		if requests != 2 {
+			t.Errorf("got %d requests, want 2", requests)
+		}
+	})
+	t.Run("failed", func(t *testing.T) {
+		requests, status, empty = 0, http.StatusServiceUnavailable, false
+		s := newService()
+		for range 2 {
+			if _, ok, err := s.LastYear(); !errors.Is(err, apierror.ErrHTTPStatus) || ok {
+				t.Fatalf("got %v, %v", ok, err)
+			}
+		}
+		if requests != 1 {
+			t.Errorf("got %d requests, want 1", requests)
+		}
+		for _, r := range s.lastYear {
+			if d := time.Until(r.expires); d <= 0 || d > lastYearFailedTTL {
+				t.Errorf("failed result expires in %s", d)
+			}
+		}
+		status = http.StatusOK
+		expire(s)
+		if _, ok, err := s.LastYear(); err != nil || !ok {
+			t.Errorf("after expiry: got %v, %v", ok, err)
+		}
+		if requests != 2 {
+			t.Errorf("got %d requests, want 2", requests)
+		}
+	})
+	t.Run("no archive", func(t *testing.T) {
+		s := New(nil, nil)
+		if _, _, err := s.LastYear(); !errors.Is(err, ErrNoArchive) {
+			t.Errorf("got %v, want no archive", err)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package weather
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ErikKalkoken/weatherapp/internal/forecast"
+	"github.com/ErikKalkoken/weatherapp/internal/location"
+)
+
+// Site is the weather at a saved location.
+type Site struct {
+	Name     string
+	Location location.Location
+	Forecast forecast.Result
+	Err      error // reason why there is no forecast
+}
+
+// Sites fetches the weather for many saved locations at once.
+// It is safe to use from multiple goroutines.
+type Sites struct {
+	names   []string
+	search  func(name string) (location.Location, error)
+	service *Service
+
+	mu    sync.Mutex
+	found map[string]location.Location // resolved locations by name
+}
+
+// NewSites returns new sites for locations given by name. The names are looked up with search.
+// The forecasts are fetched with the same settings as service.
+func NewSites(service *Service, search func(name string) (location.Location, error), names []string) *Sites {
+	s := &Sites{
+		found:   make(map[string]location.Location),
+		names:   names,
+		search:  search,
+		service: service,
+	}
+	return s
+}
+
+// Len returns the number of sites.
+func (s *Sites) Len() int {
+	return len(s.names)
+}
+
+// Refresh fetches the current weather for all sites. The sites are returned in the configured order.
+// It only returns an error when the weather could not be fetched for any site.
+func (s *Sites) Refresh() ([]Site, error) {
+	sites := make([]Site, len(s.names))
+	coords := make([]forecast.Coordinates, 0, len(s.names))
+	indexes := make([]int, 0, len(s.names)) // indexes of the sites with coordinates
+	for i, name := range s.names {
+		sites[i].Name = name
+		loc, err := s.lookup(name)
+		if err != nil {
+			sites[i].Err = err
+			continue
+		}
+		sites[i].Location = loc
+		coords = append(coords, forecast.Coordinates{Latitude: loc.Latitude, Longitude: loc.Longitude})
+		indexes = append(indexes, i)
+	}
+	var failed int
+	for j, r := range s.service.forecasts.GetBatch(coords, 0) {
+		i := indexes[j]
+		sites[i].Forecast = r.Result
+		sites[i].Err = r.Err
+	}
+	for _, x := range sites {
+		if x.Err != nil {
+			failed++
+		}
+	}
+	if len(sites) > 0 && failed == len(sites) {
+		return sites, fmt.Errorf("fetching weather for sites: %w", sites[0].Err)
+	}
+	return sites, nil
+}
+
+// lookup returns the location for a name. Found locations are remembered.
+func (s *Sites) lookup(name string) (location.Location, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if loc, ok := s.found[name]; ok {
+		return loc, nil
+	}
+	loc, err := s.search(name)
+	if err != nil {
+		return location.Location{}, err
+	}
+	s.found[name] = loc
+	return loc, nil
+}
+
+// Service returns a weather service for a site, which is configured like the service of the sites.
+func (s *Sites) Service(x Site) *Service {
+	loc := x.Location
+	o := s.service
+	svc := New(o.forecasts, func() (location.Location, error) {
+		return loc, nil
+	})
+	svc.AlertRules = o.AlertRules
+	svc.Archive = o.Archive
+	svc.CAPPoller = o.CAPPoller
+	svc.Models = o.Models
+	return svc
+}
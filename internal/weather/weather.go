@@ -0,0 +1,122 @@
+// Package weather fetches the weather for the current location and keeps the latest result in memory.
+package weather
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ErikKalkoken/weatherapp/internal/alerts"
+	"github.com/ErikKalkoken/weatherapp/internal/archive"
+	"github.com/ErikKalkoken/weatherapp/internal/cap"
+	"github.com/ErikKalkoken/weatherapp/internal/forecast"
+	"github.com/ErikKalkoken/weatherapp/internal/location"
+)
+
+// Snapshot is the weather for a location at a point in time.
+type Snapshot struct {
+	Alerts    []alerts.Alert    `json:"alerts"`
+	Location  location.Location `json:"location"`
+	Forecast  forecast.Result   `json:"forecast"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Warnings  []cap.Warning     `json:"warnings"` // official warnings
+}
+
+// Observer is notified about the fetches made by a service, e.g. to record metrics.
+type Observer interface {
+	// LocationFetched is called after each lookup of the current location.
+	LocationFetched(err error)
+	// ForecastFetched is called after each request for a forecast with its duration.
+	ForecastFetched(d time.Duration, err error)
+	// SnapshotUpdated is called after each successful refresh.
+	SnapshotUpdated(x Snapshot)
+}
+
+// SnapshotFunc is an adapter to allow the use of an ordinary function as observer,
+// which is only interested in updated snapshots.
+type SnapshotFunc func(x Snapshot)
+
+func (f SnapshotFunc) LocationFetched(error)                {}
+func (f SnapshotFunc) ForecastFetched(time.Duration, error) {}
+func (f SnapshotFunc) SnapshotUpdated(x Snapshot)           { f(x) }
+
+// Service fetches weather data and caches the latest snapshot.
+// It is safe to use from multiple goroutines.
+type Service struct {
+	// AlertRules are evaluated for each new snapshot. Must be set before the first refresh.
+	AlertRules []alerts.Rule
+	// CAPPoller provides official warnings when set. Must be set before the first refresh.
+	CAPPoller *cap.Poller
+	// Archive provides the observed weather of past days when set.
+	Archive *archive.Client
+	// Models are the weather models to compare, e.g. "gfs_seamless".
+	Models []string
+
+	forecasts *forecast.Client
+	locate    func() (location.Location, error)
+	observers []Observer
+
+	mu       sync.RWMutex
+	snapshot Snapshot
+	hasData  bool
+	lastYear map[string]lastYearResult // cached weather of the same day last year
+}
+
+// New returns a new service. The location is determined by calling locate on each refresh.
+func New(forecasts *forecast.Client, locate func() (location.Location, error)) *Service {
+	s := &Service{
+		forecasts: forecasts,
+		locate:    locate,
+	}
+	return s
+}
+
+// AddObserver adds an observer which is notified about fetches.
+// Observers must be added before the first refresh.
+func (s *Service) AddObserver(o Observer) {
+	s.observers = append(s.observers, o)
+}
+
+// Refresh determines the location, fetches its weather and updates the cache.
+func (s *Service) Refresh() (Snapshot, error) {
+	loc, err := s.locate()
+	for _, o := range s.observers {
+		o.LocationFetched(err)
+	}
+	if err != nil {
+		return Snapshot{}, err
+	}
+	start := time.Now()
+	r, err := s.forecasts.Get(loc.Latitude, loc.Longitude)
+	d := time.Since(start)
+	for _, o := range s.observers {
+		o.ForecastFetched(d, err)
+	}
+	if err != nil {
+		return Snapshot{}, err
+	}
+	now := time.Now().UTC()
+	x := Snapshot{
+		Alerts:    alerts.Evaluate(s.AlertRules, r, now),
+		Location:  loc,
+		Forecast:  r,
+		UpdatedAt: now,
+	}
+	if s.CAPPoller != nil {
+		x.Warnings = s.CAPPoller.Warnings(loc, now)
+	}
+	s.mu.Lock()
+	s.snapshot = x
+	s.hasData = true
+	s.mu.Unlock()
+	for _, o := range s.observers {
+		o.SnapshotUpdated(x)
+	}
+	return x, nil
+}
+
+// Snapshot returns the latest snapshot and reports whether there is one.
+func (s *Service) Snapshot() (Snapshot, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.snapshot, s.hasData
+}
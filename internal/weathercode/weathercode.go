@@ -0,0 +1,226 @@
+// Package weathercode describes the WMO weather codes returned by Open-Meteo.
+//
+// The codes are the subset of WMO code table 4677 used by Open-Meteo.
+package weathercode
+
+import (
+	"slices"
+)
+
+// Category is the kind of weather of a code.
+type Category uint
+
+const (
+	CategoryUnknown Category = iota
+	CategoryClear
+	CategoryCloudy
+	CategoryFog
+	CategoryRain // includes drizzle and freezing rain
+	CategorySnow
+	CategoryConvective // showers and thunderstorms
+)
+
+func (c Category) String() string {
+	switch c {
+	case CategoryClear:
+		return "clear"
+	case CategoryCloudy:
+		return "cloudy"
+	case CategoryFog:
+		return "fog"
+	case CategoryRain:
+		return "rain"
+	case CategorySnow:
+		return "snow"
+	case CategoryConvective:
+		return "convective"
+	}
+	return "unknown"
+}
+
+// IsPrecipitation reports whether weather of a category brings precipitation.
+func (c Category) IsPrecipitation() bool {
+	return c == CategoryRain || c == CategorySnow || c == CategoryConvective
+}
+
+// Intensity is the intensity of precipitation.
+type Intensity uint
+
+const (
+	IntensityNone Intensity = iota
+	IntensityLight
+	IntensityModerate
+	IntensityHeavy
+)
+
+func (i Intensity) String() string {
+	switch i {
+	case IntensityLight:
+		return "light"
+	case IntensityModerate:
+		return "moderate"
+	case IntensityHeavy:
+		return "heavy"
+	}
+	return "none"
+}
+
+// Severity is how much impact the weather of a code can have on people.
+type Severity uint
+
+const (
+	SeverityNone Severity = iota
+	SeverityMinor
+	SeverityModerate
+	SeveritySevere
+	SeverityExtreme
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityMinor:
+		return "minor"
+	case SeverityModerate:
+		return "moderate"
+	case SeveritySevere:
+		return "severe"
+	case SeverityExtreme:
+		return "extreme"
+	}
+	return "none"
+}
+
+// Icon is the name of a weather icon, e.g. "partly-cloudy".
+type Icon string
+
+const (
+	IconUnknown           Icon = "unknown"
+	IconCloudy            Icon = "cloudy"
+	IconDust              Icon = "dust"
+	IconFog               Icon = "fog"
+	IconHail              Icon = "hail"
+	IconHurricane         Icon = "hurricane"
+	IconLightning         Icon = "lightning"
+	IconLightningRainy    Icon = "lightning-rainy"
+	IconNight             Icon = "night"
+	IconNightPartlyCloudy Icon = "night-partly-cloudy"
+	IconPartlyCloudy      Icon = "partly-cloudy"
+	IconPartlyLightning   Icon = "partly-lightning"
+	IconPartlyRainy       Icon = "partly-rainy"
+	IconPartlySnowy       Icon = "partly-snowy"
+	IconPartlySnowyRainy  Icon = "partly-snowy-rainy"
+	IconPouring           Icon = "pouring"
+	IconRainy             Icon = "rainy"
+	IconSnowy             Icon = "snowy"
+	IconSnowyHeavy        Icon = "snowy-heavy"
+	IconSnowyRainy        Icon = "snowy-rainy"
+	IconSunny             Icon = "sunny"
+	IconWindy             Icon = "windy"
+)
+
+// Icons returns the names of all weather icons, except for the unknown icon.
+func Icons() []Icon {
+	return []Icon{
+		IconCloudy,
+		IconDust,
+		IconFog,
+		IconHail,
+		IconHurricane,
+		IconLightning,
+		IconLightningRainy,
+		IconNight,
+		IconNightPartlyCloudy,
+		IconPartlyCloudy,
+		IconPartlyLightning,
+		IconPartlyRainy,
+		IconPartlySnowy,
+		IconPartlySnowyRainy,
+		IconPouring,
+		IconRainy,
+		IconSnowy,
+		IconSnowyHeavy,
+		IconSnowyRainy,
+		IconSunny,
+		IconWindy,
+	}
+}
+
+// Info describes a weather code.
+type Info struct {
+	Code        int
+	Category    Category
+	Intensity   Intensity
+	Severity    Severity
+	Short       string // e.g. "drizzle"
+	Description string // e.g. "dense drizzle"
+	DayIcon     Icon
+	NightIcon   Icon
+}
+
+// Icon returns the icon for the day or the night.
+func (x Info) Icon(isDay bool) Icon {
+	if isDay {
+		return x.DayIcon
+	}
+	return x.NightIcon
+}
+
+var infos = []Info{
+	{0, CategoryClear, IntensityNone, SeverityNone, "clear", "clear sky", IconSunny, IconNight},
+	{1, CategoryClear, IntensityNone, SeverityNone, "mainly clear", "mainly clear", IconSunny, IconNight},
+	{2, CategoryCloudy, IntensityNone, SeverityNone, "partly cloudy", "partly cloudy", IconPartlyCloudy, IconNightPartlyCloudy},
+	{3, CategoryCloudy, IntensityNone, SeverityNone, "overcast", "overcast", IconCloudy, IconCloudy},
+	{45, CategoryFog, IntensityNone, SeverityMinor, "fog", "fog", IconFog, IconFog},
+	{48, CategoryFog, IntensityNone, SeverityModerate, "rime fog", "depositing rime fog", IconFog, IconFog},
+	{51, CategoryRain, IntensityLight, SeverityMinor, "drizzle", "light drizzle", IconRainy, IconRainy},
+	{53, CategoryRain, IntensityModerate, SeverityMinor, "drizzle", "moderate drizzle", IconRainy, IconRainy},
+	{55, CategoryRain, IntensityHeavy, SeverityMinor, "drizzle", "dense drizzle", IconRainy, IconRainy},
+	{56, CategoryRain, IntensityLight, SeverityModerate, "freezing drizzle", "light freezing drizzle", IconSnowyRainy, IconSnowyRainy},
+	{57, CategoryRain, IntensityHeavy, SeveritySevere, "freezing drizzle", "dense freezing drizzle", IconSnowyRainy, IconSnowyRainy},
+	{61, CategoryRain, IntensityLight, SeverityMinor, "rain", "slight rain", IconRainy, IconRainy},
+	{63, CategoryRain, IntensityModerate, SeverityModerate, "rain", "moderate rain", IconPouring, IconPouring},
+	{65, CategoryRain, IntensityHeavy, SeveritySevere, "heavy rain", "heavy rain", IconPouring, IconPouring},
+	{66, CategoryRain, IntensityLight, SeverityModerate, "freezing rain", "light freezing rain", IconSnowyRainy, IconSnowyRainy},
+	{67, CategoryRain, IntensityHeavy, SeveritySevere, "freezing rain", "heavy freezing rain", IconSnowyRainy, IconSnowyRainy},
+	{71, CategorySnow, IntensityLight, SeverityMinor, "snow", "slight snow fall", IconSnowy, IconSnowy},
+	{73, CategorySnow, IntensityModerate, SeverityModerate, "snow", "moderate snow fall", IconSnowy, IconSnowy},
+	{75, CategorySnow, IntensityHeavy, SeveritySevere, "heavy snow", "heavy snow fall", IconSnowyHeavy, IconSnowyHeavy},
+	{77, CategorySnow, IntensityLight, SeverityMinor, "snow grains", "snow grains", IconSnowy, IconSnowy},
+	{80, CategoryConvective, IntensityLight, SeverityMinor, "showers", "slight rain showers", IconPartlyRainy, IconRainy},
+	{81, CategoryConvective, IntensityModerate, SeverityModerate, "showers", "moderate rain showers", IconPartlyRainy, IconRainy},
+	{82, CategoryConvective, IntensityHeavy, SeveritySevere, "heavy showers", "violent rain showers", IconPouring, IconPouring},
+	{85, CategoryConvective, IntensityLight, SeverityMinor, "snow showers", "slight snow showers", IconPartlySnowy, IconSnowy},
+	{86, CategoryConvective, IntensityHeavy, SeveritySevere, "snow showers", "heavy snow showers", IconSnowyHeavy, IconSnowyHeavy},
+	{95, CategoryConvective, IntensityModerate, SeveritySevere, "thunderstorm", "thunderstorms", IconLightningRainy, IconLightningRainy},
+	{96, CategoryConvective, IntensityLight, SeveritySevere, "thunderstorm", "thunderstorms with slight hail", IconHail, IconHail},
+	{99, CategoryConvective, IntensityHeavy, SeverityExtreme, "thunderstorm", "thunderstorms with heavy hail", IconHail, IconHail},
+}
+
+// All returns the descriptions of all known weather codes ordered by code.
+func All() []Info {
+	return slices.Clone(infos)
+}
+
+// Lookup returns the description of a weather code and reports whether the code is known.
+// Unknown codes are described as "unknown".
+func Lookup(code int) (Info, bool) {
+	i, found := slices.BinarySearchFunc(infos, code, func(x Info, code int) int {
+		return x.Code - code
+	})
+	if !found {
+		return Info{
+			Code:        code,
+			Short:       "unknown",
+			Description: "unknown",
+			DayIcon:     IconUnknown,
+			NightIcon:   IconUnknown,
+		}, false
+	}
+	return infos[i], true
+}
+
+// Description returns the description of a weather code, e.g. "dense drizzle".
+func Description(code int) string {
+	x, _ := Lookup(code)
+	return x.Description
+}
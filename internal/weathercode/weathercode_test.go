@@ -0,0 +1,76 @@
+package weathercode
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAll(t *testing.T) {
+	all := All()
+	for i, x := range all {
+		if i > 0 && all[i-1].Code >= x.Code {
+			t.Errorf("codes not ordered: %d after %d", x.Code, all[i-1].Code)
+		}
+		if x.Category == CategoryUnknown {
+			t.Errorf("%d: no category", x.Code)
+		}
+		if x.Short == "" || x.Description == "" {
+			t.Errorf("%d: missing description", x.Code)
+		}
+		for _, icon := range []Icon{x.DayIcon, x.NightIcon} {
+			if !slices.Contains(Icons(), icon) {
+				t.Errorf("%d: unknown icon: %q", x.Code, icon)
+			}
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	// Codes returned by Open-Meteo
+	codes := []int{0, 1, 2, 3, 45, 48, 51, 53, 55, 56, 57, 61, 63, 65, 66, 67, 71, 73, 75, 77, 80, 81, 82, 85, 86, 95, 96, 99}
+	for _, code := range codes {
+		x, ok := Lookup(code)
+		if !ok {
+			t.Errorf("%d: not found", code)
+			continue
+		}
+		if x.Code != code {
+			t.Errorf("%d: got code %d", code, x.Code)
+		}
+	}
+	if len(All()) != len(codes) {
+		t.Errorf("got %d codes, want %d", len(All()), len(codes))
+	}
+	x, ok := Lookup(52)
+	if ok {
+		t.Error("52: expected unknown code")
+	}
+	if x.Description != "unknown" || x.Icon(true) != IconUnknown || x.Icon(false) != IconUnknown {
+		t.Errorf("52: got %+v", x)
+	}
+}
+
+func TestCategory(t *testing.T) {
+	cases := []struct {
+		code          int
+		category      Category
+		precipitation bool
+	}{
+		{0, CategoryClear, false},
+		{2, CategoryCloudy, false},
+		{48, CategoryFog, false},
+		{55, CategoryRain, true},
+		{75, CategorySnow, true},
+		{82, CategoryConvective, true},
+		{99, CategoryConvective, true},
+	}
+	for _, tc := range cases {
+		x, _ := Lookup(tc.code)
+		if x.Category != tc.category {
+			t.Errorf("%d: got %s, want %s", tc.code, x.Category, tc.category)
+		}
+		if x.Category.IsPrecipitation() != tc.precipitation {
+			t.Errorf("%d: got precipitation %v", tc.code, x.Category.IsPrecipitation())
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Package wind describes wind speeds and directions, e.g. with the Beaufort scale.
+package wind
+
+import (
+	"math"
+)
+
+// beaufortLimits are the upper limits of the wind speeds in m/s for each Beaufort number up to 11.
+var beaufortLimits = []float64{0.5, 1.5, 3.3, 5.5, 7.9, 10.7, 13.8, 17.1, 20.7, 24.4, 28.4, 32.6}
+
+var beaufortDescriptions = []string{
+	"calm",
+	"light air",
+	"light breeze",
+	"gentle breeze",
+	"moderate breeze",
+	"fresh breeze",
+	"strong breeze",
+	"near gale",
+	"gale",
+	"strong gale",
+	"storm",
+	"violent storm",
+	"hurricane force",
+}
+
+// Beaufort returns the Beaufort number (0-12) for a wind speed in km/h.
+func Beaufort(kmh float64) int {
+	ms := kmh / 3.6
+	for i, limit := range beaufortLimits {
+		if ms < limit {
+			return i
+		}
+	}
+	return 12
+}
+
+// BeaufortDescription returns the description of a Beaufort number, e.g. "gentle breeze".
+func BeaufortDescription(n int) string {
+	if n < 0 || n >= len(beaufortDescriptions) {
+		return "unknown"
+	}
+	return beaufortDescriptions[n]
+}
+
+var compassPoints = []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+
+// CompassPoint returns the point of a 16-point compass for a direction in degrees, e.g. "NNE".
+func CompassPoint(degrees float64) string {
+	i := int(math.Round(normalize(degrees)/22.5)) % len(compassPoints)
+	return compassPoints[i]
+}
+
+var arrows = []string{"↓", "↙", "←", "↖", "↑", "↗", "→", "↘"}
+
+// Arrow returns an arrow showing where the wind blows to for the direction it comes from in degrees.
+// For example wind from the north is shown as "↓".
+func Arrow(degrees float64) string {
+	i := int(math.Round(normalize(degrees)/45)) % len(arrows)
+	return arrows[i]
+}
+
+// ToKMH converts a wind speed in a unit returned by Open-Meteo to km/h.
+func ToKMH(v float64, unit string) float64 {
+	switch unit {
+	case "m/s":
+		return v * 3.6
+	case "mp/h", "mph":
+		return v * 1.609344
+	case "kn":
+		return v * 1.852
+	}
+	return v
+}
+
+// normalize returns a direction in degrees between 0 and 360.
+func normalize(degrees float64) float64 {
+	d := math.Mod(degrees, 360)
+	if d < 0 {
+		d += 360
+	}
+	return d
+}
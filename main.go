@@ -1,37 +1,107 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"log"
-	"net/http"
-	"time"
+	"os"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
+	"fyne.io/fyne/v2/container"
+	"github.com/ErikKalkoken/weatherapp/internal/config"
+	"github.com/ErikKalkoken/weatherapp/internal/scheduler"
 	"github.com/ErikKalkoken/weatherapp/internal/ui"
 )
 
-const (
-	updateTicker   = 60 * time.Second
-	requestTimeout = 30 * time.Second
-)
-
 func main() {
+	cfg, args, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	} else if err != nil {
+		log.Fatal(err)
+	}
+	service, err := newService(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(args) > 0 {
+		switch args[0] {
+		case "serve":
+			if err := runServer(cfg, service, args[1:]); err != nil {
+				log.Fatal(err)
+			}
+		case "ics":
+			if err := runICS(service, args[1:]); err != nil {
+				log.Fatal(err)
+			}
+		case "bar":
+			runBar(cfg, service, args[1:])
+		case "summary":
+			runSummary(service, args[1:])
+		default:
+			log.Fatalf("unknown command: %s", args[0])
+		}
+		return
+	}
 	a := app.New()
+	a.Settings().SetTheme(ui.NewTheme(cfg.Theme))
+	ui.SetBackgroundTint(cfg.ThemeTint)
 	w := a.NewWindow("Weather")
-	client := &http.Client{
-		Timeout: requestTimeout,
+	history := openHistory(cfg, service)
+	if p := loadIconPack(cfg); p != nil {
+		ui.SetIconPack(cfg.IconPack, p)
+	}
+	if dir, err := iconPacksDir(cfg); err != nil {
+		log.Printf("ERROR: icon packs: %s", err)
+	} else {
+		ui.SetIconPacksDir(dir)
+	}
+	u := ui.New(w, service, cfg.ForecastDays, history)
+	refresh := u.Refresh
+	sites, err := newSites(cfg, service)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if sites != nil {
+		o := ui.NewOverview(sites, cfg.ForecastDays)
+		w.SetContent(container.NewAppTabs(
+			container.NewTabItem("Forecast", u.Content),
+			container.NewTabItem("Locations", o.Content),
+		))
+		refresh = func() error {
+			return errors.Join(u.Refresh(), o.Refresh())
+		}
+		u.OnIconPackChanged = o.RefreshIcons
+	} else {
+		w.SetContent(u.Content)
 	}
-	u := ui.New(w, client)
-	w.SetContent(u.Content)
 	w.Resize(fyne.NewSize(300, 600))
-	ticker := time.NewTicker(updateTicker)
-	go func() {
-		for {
-			if err := u.Refresh(); err != nil {
-				log.Println("ERROR: ", err)
-			}
-			<-ticker.C
+	sched := scheduler.New(refresh, cfg.RefreshInterval)
+	poller := service.CAPPoller
+	if poller != nil {
+		poller.OnChange = sched.Trigger
+	}
+	a.Lifecycle().SetOnStarted(func() {
+		if poller != nil {
+			poller.Start()
+		}
+		sched.Start()
+	})
+	a.Lifecycle().SetOnEnteredForeground(func() {
+		sched.SetVisible(true)
+	})
+	a.Lifecycle().SetOnExitedForeground(func() {
+		sched.SetVisible(false)
+	})
+	a.Lifecycle().SetOnStopped(func() {
+		sched.Stop()
+		if poller != nil {
+			poller.Stop()
+		}
+		if history != nil {
+			history.Close()
 		}
-	}()
+	})
 	w.ShowAndRun()
 }
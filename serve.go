@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ErikKalkoken/weatherapp/internal/config"
+	"github.com/ErikKalkoken/weatherapp/internal/metrics"
+	"github.com/ErikKalkoken/weatherapp/internal/mqtt"
+	"github.com/ErikKalkoken/weatherapp/internal/scheduler"
+	"github.com/ErikKalkoken/weatherapp/internal/server"
+	"github.com/ErikKalkoken/weatherapp/internal/weather"
+)
+
+// runServer runs the app headless and serves the weather data over HTTP.
+// It returns after a shutdown on SIGINT or SIGTERM, or when the server fails.
+func runServer(cfg config.Config, service *weather.Service, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+	withMetrics := fs.Bool("metrics", false, "enable Prometheus metrics at /metrics")
+	mqttBroker := fs.String("mqtt-broker", "", "publish to this MQTT broker, e.g. tcp://localhost:1883")
+	mqttPrefix := fs.String("mqtt-prefix", "weatherapp", "topic prefix for MQTT messages")
+	mqttDiscovery := fs.String("mqtt-discovery", "homeassistant", "prefix for Home Assistant discovery messages; empty to disable")
+	mqttUser := fs.String("mqtt-user", "", "username for the MQTT broker; the password is read from WEATHERAPP_MQTT_PASSWORD")
+	fs.Parse(args)
+	srv := server.New(service)
+	if *withMetrics {
+		m := metrics.New()
+		service.AddObserver(m)
+		srv.Handle("GET /metrics", m.Handler())
+	}
+	if *mqttBroker != "" {
+		p, err := mqtt.New(mqtt.Config{
+			Broker:          *mqttBroker,
+			Username:        *mqttUser,
+			Password:        os.Getenv("WEATHERAPP_MQTT_PASSWORD"),
+			TopicPrefix:     *mqttPrefix,
+			DiscoveryPrefix: *mqttDiscovery,
+		})
+		if err != nil {
+			return err
+		}
+		defer p.Close()
+		service.AddObserver(p.Observer())
+	}
+	if st := openHistory(cfg, service); st != nil {
+		defer st.Close()
+	}
+	sched := scheduler.New(func() error {
+		_, err := service.Refresh()
+		return err
+	}, cfg.RefreshInterval)
+	if p := service.CAPPoller; p != nil {
+		p.OnChange = sched.Trigger
+		p.Start()
+		defer p.Stop()
+	}
+	sched.Start()
+	defer sched.Stop()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	hs := &http.Server{Addr: *addr, Handler: srv}
+	shutdown := make(chan struct{})
+	go func() {
+		defer close(shutdown)
+		<-ctx.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := hs.Shutdown(ctx); err != nil {
+			log.Printf("ERROR: shutting down server: %s", err)
+		}
+	}()
+	fmt.Printf("Serving weather data on http://%s\n", *addr)
+	if err := hs.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	// ListenAndServe returns right away on shutdown, so wait for open requests to complete.
+	<-shutdown
+	return nil
+}
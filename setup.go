@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ErikKalkoken/weatherapp/internal/archive"
+	"github.com/ErikKalkoken/weatherapp/internal/cap"
+	"github.com/ErikKalkoken/weatherapp/internal/config"
+	"github.com/ErikKalkoken/weatherapp/internal/forecast"
+	"github.com/ErikKalkoken/weatherapp/internal/iconpack"
+	"github.com/ErikKalkoken/weatherapp/internal/location"
+	"github.com/ErikKalkoken/weatherapp/internal/store"
+	"github.com/ErikKalkoken/weatherapp/internal/weather"
+)
+
+// newHTTPClient returns a new HTTP client for all API requests.
+func newHTTPClient(cfg config.Config) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.Proxy != "" {
+		u, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+	client := &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: transport,
+	}
+	return client, nil
+}
+
+// newLocationClient returns a new client for looking up locations.
+func newLocationClient(cfg config.Config, client *http.Client) *location.Client {
+	lc := location.NewClient(client)
+	lc.GeocodingURL = cfg.GeocodingURL
+	lc.IPURL = cfg.LocationURL
+	return lc
+}
+
+// newService returns a new weather service for a configuration.
+func newService(cfg config.Config) (*weather.Service, error) {
+	client, err := newHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	fc := forecast.NewClient(client)
+	fc.BaseURL = cfg.ForecastURL
+	fc.Days = cfg.ForecastDays
+	fc.PrecipitationUnit = cfg.PrecipitationUnit
+	fc.TemperatureUnit = cfg.TemperatureUnit
+	fc.WindSpeedUnit = cfg.WindSpeedUnit
+	lc := newLocationClient(cfg, client)
+	s := weather.New(fc, newLocator(cfg, lc))
+	s.AlertRules = cfg.Alerts
+	s.Models = cfg.Models
+	ac := archive.NewClient(client)
+	ac.BaseURL = cfg.ArchiveURL
+	ac.PrecipitationUnit = cfg.PrecipitationUnit
+	ac.TemperatureUnit = cfg.TemperatureUnit
+	ac.WindSpeedUnit = cfg.WindSpeedUnit
+	s.Archive = ac
+	if cfg.CAPFeedURL != "" {
+		s.CAPPoller = cap.NewPoller(cap.NewFeed(client, cfg.CAPFeedURL), cfg.CAPPollInterval)
+	}
+	return s, nil
+}
+
+// newSites returns the sites for the locations overview or nil when no locations are configured.
+func newSites(cfg config.Config, service *weather.Service) (*weather.Sites, error) {
+	if len(cfg.Locations) == 0 {
+		return nil, nil
+	}
+	client, err := newHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	lc := newLocationClient(cfg, client)
+	return weather.NewSites(service, lc.Search, cfg.Locations), nil
+}
+
+// openHistory opens the history store and records all updates of the service in it.
+// It returns nil when the history is disabled or can not be opened.
+func openHistory(cfg config.Config, service *weather.Service) *store.Store {
+	if !cfg.History {
+		return nil
+	}
+	path := cfg.HistoryPath
+	if path == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			log.Printf("ERROR: history disabled: %s", err)
+			return nil
+		}
+		path = filepath.Join(dir, "weatherapp", "history.db")
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			log.Printf("ERROR: history disabled: %s", err)
+			return nil
+		}
+	}
+	st, err := store.Open(path)
+	if err != nil {
+		log.Printf("ERROR: history disabled: %s", err)
+		return nil
+	}
+	service.AddObserver(st.Observer())
+	return st
+}
+
+// iconPacksDir returns the directory with the icon packs.
+func iconPacksDir(cfg config.Config) (string, error) {
+	if cfg.IconPacksDir != "" {
+		return cfg.IconPacksDir, nil
+	}
+	d, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(d, "weatherapp", "icons"), nil
+}
+
+// loadIconPack returns the configured icon pack or nil for the bundled icons.
+func loadIconPack(cfg config.Config) *iconpack.Pack {
+	if cfg.IconPack == "" {
+		return nil
+	}
+	dir, err := iconPacksDir(cfg)
+	if err != nil {
+		log.Printf("ERROR: using bundled icons: %s", err)
+		return nil
+	}
+	p, err := iconpack.Load(filepath.Join(dir, cfg.IconPack))
+	if err != nil {
+		log.Printf("ERROR: using bundled icons: %s", err)
+		if names, err := iconpack.List(dir); err == nil {
+			log.Printf("INFO: available icon packs in %s: %s", dir, strings.Join(names, ", "))
+		}
+		return nil
+	}
+	return p
+}
+
+// newLocator returns a function for determining the location to show the weather for.
+// This is either a fixed location from the configuration or the current location of this machine.
+func newLocator(cfg config.Config, lc *location.Client) func() (location.Location, error) {
+	if cfg.HasCoordinates() {
+		loc := location.Location{
+			City:      cfg.City,
+			Latitude:  cfg.Latitude,
+			Longitude: cfg.Longitude,
+		}
+		if loc.City == "" {
+			loc.City = fmt.Sprintf("%.2f, %.2f", cfg.Latitude, cfg.Longitude)
+		}
+		return func() (location.Location, error) {
+			return loc, nil
+		}
+	}
+	if cfg.City != "" {
+		var (
+			mu    sync.Mutex
+			found *location.Location
+		)
+		return func() (location.Location, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if found != nil {
+				return *found, nil
+			}
+			loc, err := lc.Search(cfg.City)
+			if err != nil {
+				return location.Location{}, err
+			}
+			found = &loc
+			return loc, nil
+		}
+	}
+	return lc.Current
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ErikKalkoken/weatherapp/internal/weather"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// runSummary fetches the weather once and prints the current weather with a summary of the next 24 hours.
+func runSummary(service *weather.Service, args []string) {
+	fs := flag.NewFlagSet("summary", flag.ExitOnError)
+	fs.Parse(args)
+	x, err := service.Refresh()
+	if err != nil {
+		log.Fatal(err)
+	}
+	c := x.Forecast.Current
+	fmt.Printf(
+		"%s / %s: %.0f°, %s\n",
+		x.Location.City, x.Location.Country, c.Temperature2m, cases.Title(language.English).String(c.Description()),
+	)
+	fmt.Println(x.Forecast.Summary(time.Now()))
+}